@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// archivePrefix keys the session state of a past day played from the
+// archive, apart from the state of the day it was played live.
+const archivePrefix = "archive:"
+
+// defaultEpoch is the first day of the schedule unless POKEDLE_EPOCH says
+// otherwise.
+const defaultEpoch = "2025-01-01"
+
+// puzzle is the day a request plays: today, or a past day with ?date=.
+type puzzle struct {
+	t       time.Time
+	day     string
+	archive bool
+}
+
+// key is the session key of the puzzle's state.
+func (p puzzle) key() string {
+	if p.archive {
+		return archivePrefix + p.day
+	}
+	return p.day
+}
+
+// doneMessage is the error of a guess on a finished puzzle.
+func (p puzzle) doneMessage(why string) string {
+	if p.archive {
+		return why + " Pick another day from the archive!"
+	}
+	return why + " Try tomorrow!"
+}
+
+func isArchiveKey(key string) bool {
+	return strings.HasPrefix(key, archivePrefix)
+}
+
+// loadEpoch reads POKEDLE_EPOCH, the first day of the schedule and of the
+// archive. It cannot be in the future.
+func loadEpoch() (time.Time, error) {
+	v := os.Getenv("POKEDLE_EPOCH")
+	if v == "" {
+		v = defaultEpoch
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil || t.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("invalid POKEDLE_EPOCH %q", v)
+	}
+	return t, nil
+}
+
+// puzzle returns the puzzle of the ?date= parameter, today's when it is
+// missing or today. Future days and days before the epoch are rejected.
+func (s *Server) puzzle(r *http.Request) (puzzle, error) {
+	now := time.Now().UTC()
+	today := dayKey(now)
+	v := r.URL.Query().Get("date")
+	if v == "" || v == today {
+		return puzzle{t: now, day: today}, nil
+	}
+
+	t, err := time.Parse("2006-01-02", v)
+	switch {
+	case err != nil:
+		return puzzle{}, fmt.Errorf("invalid date %q", v)
+	case t.After(now):
+		return puzzle{}, fmt.Errorf("the puzzle of %s is not out yet", v)
+	case t.Before(s.epoch):
+		return puzzle{}, fmt.Errorf("the archive starts on %s", dayKey(s.epoch))
+	}
+	return puzzle{t: t, day: v, archive: true}, nil
+}
+
+// archived returns the player's archive games keyed by day.
+func (st *SessionStore) archived(id string) map[string]DayState {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	out := make(map[string]DayState)
+	if sess, ok := st.sessions[id]; ok {
+		for key, ds := range sess.Days {
+			if day, ok := strings.CutPrefix(key, archivePrefix); ok {
+				out[day] = ds.copy()
+			}
+		}
+	}
+	return out
+}
+
+// handleArchive lists the past days from yesterday back to the epoch, with
+// the player's status for each: "won", "lost", "playing" or "new".
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
+	sid := s.sessions.sessionID(r)
+	played := s.sessions.archived(sid)
+	type archiveDay struct {
+		Date    string `json:"date"`
+		Puzzle  int    `json:"puzzle"`
+		Status  string `json:"status"`
+		Guesses int    `json:"guesses"`
+	}
+	days := []archiveDay{}
+	for t := time.Now().UTC().AddDate(0, 0, -1); !t.Before(s.epoch); t = t.AddDate(0, 0, -1) {
+		day := dayKey(t)
+		status := "new"
+		ds := played[day]
+		switch {
+		case ds.Solved:
+			status = "won"
+		case ds.Lost:
+			status = "lost"
+		case len(ds.Guesses) > 0:
+			status = "playing"
+		}
+		days = append(days, archiveDay{
+			Date:    day,
+			Puzzle:  s.schedule.number(t),
+			Status:  status,
+			Guesses: len(ds.Guesses),
+		})
+	}
+	writeJSON(w, days)
+}
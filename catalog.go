@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CatalogEntry is the offline copy of everything the game needs about a
+// Pokémon. It is generated by
+// `go run ./cmd/pokedle-data build catalog`.
+type CatalogEntry struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	Types        []string          `json:"types"`
+	Height       int               `json:"height"`
+	Weight       int               `json:"weight"`
+	Sprite       string            `json:"sprite"`
+	Cry          string            `json:"cry"`
+	Descriptions map[string]string `json:"descriptions"`
+}
+
+type Catalog struct {
+	byID map[int]*CatalogEntry
+}
+
+func loadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []CatalogEntry
+	if err := json.NewDecoder(f).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c := &Catalog{byID: make(map[int]*CatalogEntry, len(entries))}
+	for i := range entries {
+		c.byID[entries[i].ID] = &entries[i]
+	}
+	return c, nil
+}
+
+func (c *Catalog) get(id int) (*CatalogEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	e, ok := c.byID[id]
+	return e, ok
+}
+
+// missing returns the IDs of names the catalog has no entry for.
+func (c *Catalog) missing(names *NameIndex) []int {
+	var ids []int
+	for _, row := range names.rows {
+		if _, ok := c.get(row.ID); !ok {
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids
+}
+
+func (c *Catalog) size() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.byID)
+}
+
+// pokemon converts the entry to the PokeAPI shape used by the guess hints.
+func (e *CatalogEntry) pokemon() *Pokemon {
+	p := &Pokemon{
+		ID:     e.ID,
+		Name:   e.Name,
+		Height: e.Height,
+		Weight: e.Weight,
+	}
+	p.Sprites.FrontDefault = e.Sprite
+	for i, t := range e.Types {
+		var te TypeEntry
+		te.Slot = i + 1
+		te.Type.Name = t
+		p.Types = append(p.Types, te)
+	}
+	return p
+}
+
+func (c *Catalog) entry(id int) (*CatalogEntry, error) {
+	e, ok := c.get(id)
+	if !ok {
+		return nil, fmt.Errorf("pokemon %d is not in the catalog: %w", id, errUnknownPokemon)
+	}
+	return e, nil
+}
+
+func (c *Catalog) Pokemon(id int) (*Pokemon, error) {
+	e, err := c.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.pokemon(), nil
+}
+
+func (c *Catalog) Cry(id int) (string, error) {
+	e, err := c.entry(id)
+	if err != nil {
+		return "", err
+	}
+	return e.Cry, nil
+}
+
+func (c *Catalog) Types(id int) ([]string, error) {
+	e, err := c.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for _, t := range e.Types {
+		types = append(types, strings.ToUpper(t))
+	}
+	return types, nil
+}
+
+func (c *Catalog) Descriptions(id int) (map[string]string, error) {
+	e, err := c.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.Descriptions, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type catalogPokemon struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Height int    `json:"height"`
+	Weight int    `json:"weight"`
+	Types  []struct {
+		Slot int `json:"slot"`
+		Type struct {
+			Name string `json:"name"`
+		} `json:"type"`
+	} `json:"types"`
+	Sprites struct {
+		FrontDefault string `json:"front_default"`
+		Other        map[string]struct {
+			FrontDefault string `json:"front_default"`
+		} `json:"other"`
+	} `json:"sprites"`
+	Cries struct {
+		Latest string `json:"latest"`
+	} `json:"cries"`
+	Species struct {
+		URL string `json:"url"`
+	} `json:"species"`
+}
+
+type speciesFlavorTexts struct {
+	FlavorTextEntries []struct {
+		FlavorText string `json:"flavor_text"`
+		Language   struct {
+			Name string `json:"name"`
+		} `json:"language"`
+	} `json:"flavor_text_entries"`
+}
+
+// CatalogEntry mirrors the server's CatalogEntry.
+type CatalogEntry struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	Types        []string          `json:"types"`
+	Height       int               `json:"height"`
+	Weight       int               `json:"weight"`
+	Sprite       string            `json:"sprite"`
+	Cry          string            `json:"cry"`
+	Descriptions map[string]string `json:"descriptions"`
+}
+
+func (b *builder) readIDs(file string) ([]int, error) {
+	records, err := readCSV(filepath.Join(b.dataDir, file))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for i, row := range records {
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(row[0]))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func cleanFlavorText(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	text = strings.ReplaceAll(text, "\f", " ")
+	return strings.TrimSpace(text)
+}
+
+func (b *builder) catalogEntry(id int) (*CatalogEntry, error) {
+	var p catalogPokemon
+	if err := b.api.GetJSON(fmt.Sprintf("pokemon/%d", id), &p); err != nil {
+		return nil, err
+	}
+
+	entry := &CatalogEntry{
+		ID:           p.ID,
+		Name:         p.Name,
+		Height:       p.Height,
+		Weight:       p.Weight,
+		Sprite:       p.Sprites.FrontDefault,
+		Cry:          p.Cries.Latest,
+		Descriptions: map[string]string{},
+	}
+	if oa, ok := p.Sprites.Other["official-artwork"]; ok && oa.FrontDefault != "" {
+		entry.Sprite = oa.FrontDefault
+	}
+
+	types := make([]string, 2)
+	for _, t := range p.Types {
+		if t.Slot >= 1 && t.Slot <= 2 {
+			types[t.Slot-1] = t.Type.Name
+		}
+	}
+	for _, t := range types {
+		if t != "" {
+			entry.Types = append(entry.Types, t)
+		}
+	}
+
+	var species speciesFlavorTexts
+	if err := b.api.GetJSON(p.Species.URL, &species); err != nil {
+		return nil, err
+	}
+	for _, ft := range species.FlavorTextEntries {
+		lang := ft.Language.Name
+		if !slices.Contains(nameLanguages, lang) {
+			continue
+		}
+		if _, ok := entry.Descriptions[lang]; !ok {
+			entry.Descriptions[lang] = cleanFlavorText(ft.FlavorText)
+		}
+	}
+
+	return entry, nil
+}
+
+// buildCatalog writes pokemon_catalog.json for every ID of the names and
+// forms files, so the server can run without calling PokeAPI.
+func (b *builder) buildCatalog() error {
+	ids, err := b.readIDs("pokemon_names_multilang.csv")
+	if err != nil {
+		return err
+	}
+	formIDs, err := b.readIDs("pokemon_forms.csv")
+	if err != nil {
+		fmt.Println("No regional forms loaded:", err)
+	}
+	ids = slices.Compact(slices.Sorted(slices.Values(append(ids, formIDs...))))
+
+	catalog, err := fetchAll(b, ids, func(id int) (*CatalogEntry, bool, error) {
+		entry, err := b.catalogEntry(id)
+		if err != nil {
+			return nil, false, err
+		}
+		fmt.Println("[ADD] #", id, " - ", entry.Name)
+		return entry, true, nil
+	})
+	if err != nil {
+		return err
+	}
+	catalog = slices.DeleteFunc(catalog, func(e *CatalogEntry) bool {
+		if b.overrides.excluded(e.ID) {
+			fmt.Printf("[OVERRIDE] pokemon_catalog.json: id %d excluded (%s)\n", e.ID, b.overrides[e.ID].Note)
+			return true
+		}
+		return false
+	})
+	if catalog == nil {
+		catalog = []*CatalogEntry{}
+	}
+
+	path := filepath.Join(b.dataDir, "pokemon_catalog.json")
+	err = writeFile(path, func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(catalog)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s generated successfully: %d Pokémon\n", path, len(catalog))
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pokedle/pokeapi"
+)
+
+type listResponse struct {
+	Count   int `json:"count"`
+	Results []struct {
+		URL string `json:"url"`
+	} `json:"results"`
+}
+
+type Name struct {
+	Name     string `json:"name"`
+	Language struct {
+		Name string `json:"name"`
+	} `json:"language"`
+}
+
+// maxID binary-searches the highest ID of resource that answers 200,
+// between low and low+count-1 where count comes from the list endpoint.
+func (b *builder) maxID(resource string, low int) (int, error) {
+	var list listResponse
+	if err := b.api.GetJSON(resource, &list); err != nil {
+		return 0, fmt.Errorf("fetching %s count: %w", resource, err)
+	}
+
+	high := low + list.Count - 1
+	maxValid := 0
+	for low <= high {
+		mid := (low + high) / 2
+		ok, err := b.api.Exists(fmt.Sprintf("%s/%d", resource, mid))
+		if err != nil {
+			return 0, fmt.Errorf("checking %s %d: %w", resource, mid, err)
+		}
+		if ok {
+			maxValid = mid
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return maxValid, nil
+}
+
+func idRange(low, high int) []int {
+	var ids []int
+	for id := low; id <= high; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// fetchAll runs fetch for every ID on a pool of b.workers goroutines and
+// returns the kept results in ID order. fetch returns false for IDs to
+// skip and an error for failed requests. When any request failed nothing
+// is returned, so the caller keeps its previous file: the responses that
+// did arrive are in the disk cache and a rerun only fetches the rest.
+func fetchAll[T any](b *builder, ids []int, fetch func(id int) (T, bool, error)) ([]T, error) {
+	type result struct {
+		v   T
+		ok  bool
+		err error
+	}
+	results := make([]result, len(ids))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				v, ok, err := fetch(ids[i])
+				if err != nil {
+					fmt.Printf("Error on ID %d: %v\n", ids[i], err)
+				}
+				results[i] = result{v, ok, err}
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var kept []T
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.err != nil:
+			failed++
+		case r.ok:
+			kept = append(kept, r.v)
+		}
+	}
+	if failed > 0 {
+		return nil, fmt.Errorf("%d of %d requests failed, rerun to resume from the cache", failed, len(ids))
+	}
+	return kept, nil
+}
+
+// notFound turns a 404 into a skipped ID rather than a failure.
+func notFound(err error) error {
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func idFromURL(url string) int {
+	parts := strings.Split(strings.Trim(url, "/"), "/")
+	id, _ := strconv.Atoi(parts[len(parts)-1])
+	return id
+}
+
+// nameLanguages are the language columns of the names and forms files, in
+// order. English comes first and stands in for any missing name.
+var nameLanguages = []string{"en", "fr", "de", "es", "it", "ja", "ja-Hrkt", "ko", "zh-Hans", "zh-Hant"}
+
+// localizedNames returns one name per nameLanguages, or false when there
+// is no English name.
+func localizedNames(names []Name) ([]string, bool) {
+	en := nameInLanguage(names, "en", "")
+	if en == "" {
+		return nil, false
+	}
+	out := []string{en}
+	for _, lang := range nameLanguages[1:] {
+		out = append(out, nameInLanguage(names, lang, en))
+	}
+	return out, true
+}
+
+func nameInLanguage(names []Name, lang string, fallback string) string {
+	for _, n := range names {
+		if n.Language.Name == lang {
+			return n.Name
+		}
+	}
+	return fallback
+}
+
+func readCSV(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return csv.NewReader(file).ReadAll()
+}
+
+// writeFile replaces path atomically, so the server's hot reload never
+// sees a half-written file.
+func writeFile(path string, write func(f *os.File) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeCSV applies the overrides to rows, header first, and writes them.
+func (b *builder) writeCSV(path string, rows [][]string) error {
+	rows = b.overrides.apply(filepath.Base(path), rows)
+	err := writeFile(path, func(f *os.File) error {
+		w := csv.NewWriter(f)
+		return w.WriteAll(rows)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s generated successfully: %d rows\n", path, len(rows)-1)
+	return nil
+}
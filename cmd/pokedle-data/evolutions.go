@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+type EvolutionNode struct {
+	Species struct {
+		URL string `json:"url"`
+	} `json:"species"`
+	EvolvesTo []EvolutionNode `json:"evolves_to"`
+}
+
+type EvolutionChain struct {
+	ID    int           `json:"id"`
+	Chain EvolutionNode `json:"chain"`
+}
+
+var speciesURL = regexp.MustCompile(`/pokemon-species/(\d+)/`)
+
+func speciesIDFromURL(url string) int {
+	matches := speciesURL.FindStringSubmatch(url)
+	if len(matches) >= 2 {
+		id, _ := strconv.Atoi(matches[1])
+		return id
+	}
+	return 0
+}
+
+func traverseEvolutionChain(node EvolutionNode, position int, output *[][]string) {
+	pokemonID := speciesIDFromURL(node.Species.URL)
+	fullyEvolved := 0
+	if len(node.EvolvesTo) == 0 {
+		fullyEvolved = 1
+	}
+
+	*output = append(*output, []string{
+		strconv.Itoa(pokemonID),
+		strconv.Itoa(position),
+		strconv.Itoa(fullyEvolved),
+	})
+
+	for _, next := range node.EvolvesTo {
+		traverseEvolutionChain(next, position+1, output)
+	}
+}
+
+// buildEvolutions writes pokemon_evolution_data.csv: for every species its
+// position in its evolution line and whether it is fully evolved.
+func (b *builder) buildEvolutions() error {
+	// Chain IDs have gaps, so list them instead of searching for the max.
+	var list listResponse
+	if err := b.api.GetJSON("evolution-chain?limit=100000", &list); err != nil {
+		return fmt.Errorf("listing evolution chains: %w", err)
+	}
+	var ids []int
+	for _, r := range list.Results {
+		ids = append(ids, idFromURL(r.URL))
+	}
+
+	chains, err := fetchAll(b, ids, func(id int) (EvolutionChain, bool, error) {
+		var chain EvolutionChain
+		if err := b.api.GetJSON(fmt.Sprintf("evolution-chain/%d", id), &chain); err != nil {
+			return chain, false, err
+		}
+		return chain, true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	output := [][]string{
+		{"id", "position", "is_fully_evolved"},
+	}
+	visited := make(map[int]bool)
+	for _, chain := range chains {
+		var rows [][]string
+		traverseEvolutionChain(chain.Chain, 0, &rows)
+		for _, row := range rows {
+			id, _ := strconv.Atoi(row[0])
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			output = append(output, row)
+			fmt.Println("[ADD]#", id)
+		}
+	}
+
+	return b.writeCSV(filepath.Join(b.dataDir, "pokemon_evolution_data.csv"), output)
+}
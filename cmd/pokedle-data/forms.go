@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var formsToKeep = regexp.MustCompile(`(galar|hisui|alola|paldea)`)
+
+type PokemonForm struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Pokemon struct {
+		URL string `json:"url"`
+	} `json:"pokemon"`
+	Names []Name `json:"names"`
+}
+
+type pokemonSpeciesLink struct {
+	Species struct {
+		URL string `json:"url"`
+	} `json:"species"`
+}
+
+type evolutionRow struct {
+	Position       string
+	IsFullyEvolved string
+}
+
+func getGenerationFromName(name string) string {
+	switch {
+	case strings.Contains(name, "alola"):
+		return "7"
+	case strings.Contains(name, "galar"):
+		return "8"
+	case strings.Contains(name, "hisui"):
+		return "9"
+	case strings.Contains(name, "paldea"):
+		return "9"
+	default:
+		return ""
+	}
+}
+
+func (b *builder) loadEvolutionRows() (map[int]evolutionRow, error) {
+	records, err := readCSV(filepath.Join(b.dataDir, "pokemon_evolution_data.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[int]evolutionRow)
+	for i, row := range records {
+		if i == 0 || len(row) < 3 {
+			continue
+		}
+		speciesID, _ := strconv.Atoi(row[0])
+		data[speciesID] = evolutionRow{Position: row[1], IsFullyEvolved: row[2]}
+	}
+	return data, nil
+}
+
+// buildForms writes pokemon_forms.csv with the Alolan, Galarian, Hisuian
+// and Paldean forms. It needs pokemon_evolution_data.csv.
+func (b *builder) buildForms() error {
+	evoData, err := b.loadEvolutionRows()
+	if err != nil {
+		return err
+	}
+
+	maxID, err := b.maxID("pokemon-form", 10001)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Max form ID: %d\n", maxID)
+
+	rows, err := fetchAll(b, idRange(10001, maxID), func(formID int) ([]string, bool, error) {
+		var pf PokemonForm
+		if err := b.api.GetJSON(fmt.Sprintf("pokemon-form/%d", formID), &pf); err != nil {
+			return nil, false, notFound(err)
+		}
+
+		if !formsToKeep.MatchString(pf.Name) {
+			return nil, false, nil
+		}
+
+		pokemonID := idFromURL(pf.Pokemon.URL)
+
+		names, ok := localizedNames(pf.Names)
+		if !ok {
+			return nil, false, nil
+		}
+
+		genID := getGenerationFromName(pf.Name)
+
+		var poke pokemonSpeciesLink
+		if err := b.api.GetJSON(fmt.Sprintf("pokemon/%d", pokemonID), &poke); err != nil {
+			return nil, false, fmt.Errorf("pokemon %d: %w", pokemonID, err)
+		}
+		speciesID := idFromURL(poke.Species.URL)
+
+		position := ""
+		isFullyEvolved := ""
+		if evo, ok := evoData[speciesID]; ok {
+			position = evo.Position
+			isFullyEvolved = evo.IsFullyEvolved
+		}
+
+		fmt.Println("Added Pokémon:", names[0])
+		row := append([]string{strconv.Itoa(pokemonID)}, names...)
+		return append(row, genID, position, isFullyEvolved), true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	header := append([]string{"id"}, nameLanguages...)
+	header = append(header, "gen", "position", "is_fully_evolved")
+	return b.writeCSV(filepath.Join(b.dataDir, "pokemon_forms.csv"), append([][]string{header}, rows...))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type speciesGeneration struct {
+	Generation struct {
+		Name string `json:"name"`
+	} `json:"generation"`
+}
+
+var romanGenerations = map[string]int{
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5, "vi": 6, "vii": 7, "viii": 8, "ix": 9,
+}
+
+// generationNumber turns "generation-iv" into 4, or 0 when unknown.
+func generationNumber(name string) int {
+	roman, ok := strings.CutPrefix(name, "generation-")
+	if !ok {
+		return 0
+	}
+	return romanGenerations[roman]
+}
+
+// buildGen writes pokemon_id_gen.csv.
+func (b *builder) buildGen() error {
+	maxID, err := b.maxID("pokemon", 1)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Max valid Pokémon ID: %d\n", maxID)
+
+	rows, err := fetchAll(b, idRange(1, maxID), func(id int) ([]string, bool, error) {
+		var species speciesGeneration
+		if err := b.api.GetJSON(fmt.Sprintf("pokemon-species/%d", id), &species); err != nil {
+			return nil, false, notFound(err)
+		}
+
+		genNum := generationNumber(species.Generation.Name)
+		if genNum == 0 {
+			return nil, false, nil
+		}
+		fmt.Println("[ADD]#", id, " - ", genNum, "G")
+		return []string{strconv.Itoa(id), strconv.Itoa(genNum)}, true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	header := []string{"id", "gen"}
+	return b.writeCSV(filepath.Join(b.dataDir, "pokemon_id_gen.csv"), append([][]string{header}, rows...))
+}
@@ -0,0 +1,212 @@
+// Command pokedle-data builds the data/ files the game reads from PokeAPI.
+//
+//	pokedle-data build [-data dir] [-workers n] [-max-age d] [-langs en,...] names|gen|evolutions|forms|catalog|manifest|all ...
+//
+// Quirks of the PokeAPI data are fixed by data/overrides.json, applied to
+// every file written.
+//
+// Each build also records the files it wrote in data/manifest.json. The
+// manifest target only rehashes the files already on disk, to accept
+// hand edits.
+//
+// Every response is kept in the PokeAPI disk cache as soon as it arrives,
+// so a build that dies halfway resumes where it stopped when rerun: only
+// responses missing from the cache or older than -max-age are fetched.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"pokedle/manifest"
+	"pokedle/pokeapi"
+)
+
+type builder struct {
+	api       *pokeapi.Client
+	dataDir   string
+	workers   int
+	overrides overrides
+}
+
+type target struct {
+	name  string
+	file  string
+	build func(*builder) error
+}
+
+// targets lists the build steps in dependency order: forms reads the
+// evolution data and the catalog reads the names and forms files.
+var targets = []target{
+	{"names", "pokemon_names_multilang.csv", (*builder).buildNames},
+	{"gen", "pokemon_id_gen.csv", (*builder).buildGen},
+	{"evolutions", "pokemon_evolution_data.csv", (*builder).buildEvolutions},
+	{"forms", "pokemon_forms.csv", (*builder).buildForms},
+	{"catalog", "pokemon_catalog.json", (*builder).buildCatalog},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: pokedle-data build [-data dir] [-workers n] [-max-age d] [-langs en,...] names|gen|evolutions|forms|catalog|manifest|all ...")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "build" {
+		usage()
+	}
+
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	dataDir := fs.String("data", "data", "directory the files are written to")
+	workers := fs.Int("workers", 8, "number of concurrent PokeAPI requests")
+	langs := fs.String("langs", strings.Join(nameLanguages, ","), "name languages, comma separated, en first")
+	cfg := pokeapi.ConfigFromEnv()
+	if cfg.CacheMaxAge == 0 {
+		cfg.CacheMaxAge = 7 * 24 * time.Hour
+	}
+	fs.DurationVar(&cfg.CacheMaxAge, "max-age", cfg.CacheMaxAge, "refetch cached responses older than this")
+	fs.Usage = usage
+	fs.Parse(os.Args[2:])
+	nameLanguages = strings.Split(*langs, ",")
+	if fs.NArg() == 0 || *workers < 1 || nameLanguages[0] != "en" {
+		usage()
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range fs.Args() {
+		if name == "all" {
+			for _, t := range targets {
+				selected[t.name] = true
+			}
+			continue
+		}
+		if name != "manifest" && !isTarget(name) {
+			fmt.Fprintf(os.Stderr, "unknown target %q\n", name)
+			usage()
+		}
+		selected[name] = true
+	}
+
+	if err := os.MkdirAll(*dataDir, os.ModePerm); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ov, err := loadOverrides(filepath.Join(*dataDir, overridesFile))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	b := &builder{
+		api:       pokeapi.NewClient(cfg),
+		dataDir:   *dataDir,
+		workers:   *workers,
+		overrides: ov,
+	}
+	var built, rehashed []string
+	var added []int
+	var removed []manifest.Batch
+	for _, t := range targets {
+		if !selected[t.name] {
+			if _, err := os.Stat(filepath.Join(b.dataDir, t.file)); err == nil && selected["manifest"] {
+				rehashed = append(rehashed, t.file)
+			}
+			continue
+		}
+		before, _ := b.readIDs(t.file)
+		if err := t.build(b); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", t.name, err)
+			os.Exit(1)
+		}
+		built = append(built, t.file)
+		if before != nil && filepath.Ext(t.file) == ".csv" {
+			after, err := b.readIDs(t.file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", t.name, err)
+				os.Exit(1)
+			}
+			added = append(added, newIDs(before, after)...)
+			if gone := newIDs(after, before); len(gone) > 0 {
+				removed = append(removed, manifest.Batch{File: t.file, IDs: gone})
+			}
+		}
+	}
+	if err := b.writeManifest(built, rehashed, added, removed); err != nil {
+		fmt.Fprintf(os.Stderr, "manifest: %v\n", err)
+		os.Exit(1)
+	}
+
+	hits, fetches := b.api.Stats()
+	fmt.Printf("%d responses from cache, %d fetched\n", hits, fetches)
+}
+
+// writeManifest records the files just built in the manifest with their
+// provenance, and the checksums of the rehashed files with the provenance
+// they already had. The entries of the other files are kept as they were.
+// The IDs the build added to existing files are recorded as a new batch,
+// the ones it removed as one batch per file.
+func (b *builder) writeManifest(built, rehashed []string, added []int, removed []manifest.Batch) error {
+	m, err := manifest.Load(b.dataDir)
+	if errors.Is(err, os.ErrNotExist) {
+		m, err = &manifest.Manifest{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := m.Update(b.dataDir, rehashed...); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := m.Built(b.dataDir, b.api.BaseURL(), now, built...); err != nil {
+		return err
+	}
+	if len(added) > 0 {
+		ids := slices.Compact(slices.Sorted(slices.Values(added)))
+		m.Added = append(m.Added, manifest.Batch{At: now, IDs: ids})
+		fmt.Printf("%d Pokémon added: %v\n", len(ids), ids)
+	}
+	for _, batch := range removed {
+		batch.At = now
+		m.Removed = append(m.Removed, batch)
+		fmt.Printf("%s: %d Pokémon removed: %v\n", batch.File, len(batch.IDs), batch.IDs)
+	}
+
+	path := filepath.Join(b.dataDir, manifest.FileName)
+	err = writeFile(path, func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s generated successfully: dataset %s\n", path, m.Version)
+	return nil
+}
+
+// newIDs returns the IDs of after missing from before.
+func newIDs(before, after []int) []int {
+	var ids []int
+	for _, id := range after {
+		if !slices.Contains(before, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func isTarget(name string) bool {
+	for _, t := range targets {
+		if t.name == name {
+			return true
+		}
+	}
+	return false
+}
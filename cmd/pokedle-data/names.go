@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type speciesNames struct {
+	Names []Name `json:"names"`
+}
+
+// buildNames writes pokemon_names_multilang.csv with one column per
+// language of nameLanguages.
+func (b *builder) buildNames() error {
+	maxID, err := b.maxID("pokemon", 1)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Max valid Pokémon ID: %d\n", maxID)
+
+	rows, err := fetchAll(b, idRange(1, maxID), func(id int) ([]string, bool, error) {
+		var species speciesNames
+		if err := b.api.GetJSON(fmt.Sprintf("pokemon-species/%d", id), &species); err != nil {
+			return nil, false, notFound(err)
+		}
+
+		names, ok := localizedNames(species.Names)
+		if !ok {
+			return nil, false, nil
+		}
+		fmt.Println("[ADD] #", id, " - ", names[0])
+		return append([]string{fmt.Sprintf("%d", id)}, names...), true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	header := append([]string{"id"}, nameLanguages...)
+	return b.writeCSV(filepath.Join(b.dataDir, "pokemon_names_multilang.csv"), append([][]string{header}, rows...))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const overridesFile = "overrides.json"
+
+// override fixes the data PokeAPI returns for one pokemon or species ID.
+// Set replaces CSV columns by name, Rename replaces substrings in every
+// name column and Exclude drops the ID from every file.
+type override struct {
+	Note    string            `json:"note"`
+	Exclude bool              `json:"exclude,omitempty"`
+	Set     map[string]string `json:"set,omitempty"`
+	Rename  map[string]string `json:"rename,omitempty"`
+}
+
+type overrides map[int]override
+
+// loadOverrides reads data/overrides.json. A missing file means no
+// overrides.
+func loadOverrides(path string) (overrides, error) {
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return overrides{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var o overrides
+	if err := json.Unmarshal(body, &o); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for id, ov := range o {
+		if _, ok := ov.Set["id"]; ok {
+			return nil, fmt.Errorf("%s: id %d: the id column cannot be overridden", path, id)
+		}
+	}
+	return o, nil
+}
+
+func (o overrides) excluded(id int) bool {
+	return o[id].Exclude
+}
+
+// apply edits the rows of a CSV file, header first, and logs every change.
+func (o overrides) apply(file string, rows [][]string) [][]string {
+	if len(rows) == 0 {
+		return rows
+	}
+	header := rows[0]
+	kept := [][]string{header}
+
+	for _, row := range rows[1:] {
+		id, err := strconv.Atoi(row[0])
+		ov, ok := o[id]
+		if err != nil || !ok {
+			kept = append(kept, row)
+			continue
+		}
+		if ov.Exclude {
+			fmt.Printf("[OVERRIDE] %s: id %d excluded (%s)\n", file, id, ov.Note)
+			continue
+		}
+
+		for _, col := range slices.Sorted(maps.Keys(ov.Set)) {
+			value := ov.Set[col]
+			i := slices.Index(header, col)
+			if i < 0 || row[i] == value {
+				continue
+			}
+			fmt.Printf("[OVERRIDE] %s: id %d %s %q -> %q (%s)\n", file, id, col, row[i], value, ov.Note)
+			row[i] = value
+		}
+
+		for i, col := range header {
+			if !slices.Contains(nameLanguages, col) {
+				continue
+			}
+			if name := ov.rename(row[i]); name != row[i] {
+				fmt.Printf("[OVERRIDE] %s: id %d %s %q -> %q (%s)\n", file, id, col, row[i], name, ov.Note)
+				row[i] = name
+			}
+		}
+		kept = append(kept, row)
+	}
+	return kept
+}
+
+// rename applies the substring replacements and collapses the spaces they
+// leave behind.
+func (ov override) rename(name string) string {
+	if len(ov.Rename) == 0 {
+		return name
+	}
+	for _, from := range slices.Sorted(maps.Keys(ov.Rename)) {
+		name = strings.ReplaceAll(name, from, ov.Rename[from])
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
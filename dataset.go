@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pokedle/manifest"
+)
+
+// Dataset is everything the game reads from dataDir. It is loaded and
+// validated once, so handlers never touch the filesystem.
+type Dataset struct {
+	names *NameIndex
+	gen   map[int]int
+	evo   map[int]EvolutionData
+	forms []FormRow
+	// source answers for the Pokémon of names. It is reloaded with them.
+	source PokemonSource
+	// added and removed are when Pokémon joined or left the targets after
+	// the first build, from the manifest and from the reloads that saw it
+	// happen. The schedule only lets the change into the next cycles.
+	added   map[int]time.Time
+	removed map[int]time.Time
+
+	// manifest is nil when dataDir has none. modified lists the files that
+	// no longer match it.
+	manifest *manifest.Manifest
+	modified []string
+}
+
+const (
+	namesFile     = "pokemon_names_multilang.csv"
+	genFile       = "pokemon_id_gen.csv"
+	evolutionFile = "pokemon_evolution_data.csv"
+	formsFile     = "pokemon_forms.csv"
+	catalogFile   = "pokemon_catalog.json"
+)
+
+// loadDataset reads every data file and reports all problems at once, one
+// per line, instead of stopping at the first one.
+func loadDataset(dataDir string, regionalTargets bool) (*Dataset, error) {
+	var errs []error
+
+	names, err := loadNames(filepath.Join(dataDir, namesFile))
+	errs = append(errs, err)
+	gen, err := loadGenerationMap(filepath.Join(dataDir, genFile))
+	errs = append(errs, err)
+	evo, err := loadEvolutionData(filepath.Join(dataDir, evolutionFile))
+	errs = append(errs, err)
+	forms, err := loadForms(filepath.Join(dataDir, formsFile))
+	errs = append(errs, err)
+	m, err := manifest.Load(dataDir)
+	if !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	names.addForms(forms, regionalTargets)
+	if err := names.collisions(); err != nil {
+		return nil, err
+	}
+	names.buildIndexes()
+	applyForms(forms, gen, evo)
+	if names.maxIndex() == 0 {
+		return nil, fmt.Errorf("%s: no daily target candidates", namesFile)
+	}
+
+	data := &Dataset{
+		names:    names,
+		gen:      gen,
+		evo:      evo,
+		forms:    forms,
+		added:    make(map[int]time.Time),
+		removed:  make(map[int]time.Time),
+		manifest: m,
+	}
+	if m != nil {
+		data.modified = m.Verify(dataDir)
+		data.added = m.AddedAt()
+		data.removed = m.RemovedAt(namesFile)
+		if regionalTargets {
+			maps.Copy(data.removed, m.RemovedAt(formsFile))
+		}
+	}
+	return data, nil
+}
+
+// follow carries over the pool changes prev knew of, and dates the ones
+// from prev to d that the manifest does not record, a hand edit for
+// instance, to now.
+func (d *Dataset) follow(prev *Dataset, now time.Time) {
+	for id, at := range prev.added {
+		if _, ok := d.added[id]; !ok {
+			d.added[id] = at
+		}
+	}
+	for id, at := range prev.removed {
+		if _, ok := d.removed[id]; !ok {
+			d.removed[id] = at
+		}
+	}
+	for _, id := range d.names.targets {
+		if _, ok := d.added[id]; !ok && !slices.Contains(prev.names.targets, id) {
+			d.added[id] = now
+		}
+	}
+	for _, id := range prev.names.targets {
+		if _, ok := d.removed[id]; !ok && !slices.Contains(d.names.targets, id) {
+			d.removed[id] = now
+		}
+	}
+}
+
+// pool is what the schedule draws the targets from.
+func (d *Dataset) pool() Pool {
+	return Pool{ids: d.names.targets, added: d.added, removed: d.removed}
+}
+
+// version identifies the dataset in logs and /api/dataset.
+func (d *Dataset) version() string {
+	if d.manifest == nil {
+		return "unknown"
+	}
+	return d.manifest.Version
+}
+
+// restrict keeps only the Pokémon in keep, as names and as targets.
+func (n *NameIndex) restrict(keep []int) {
+	rows := n.rows
+	n.rows = nil
+	n.idByKey = make(map[string]int)
+	n.aliases = make(map[string]int)
+	n.enById = make(map[int]string)
+	for _, row := range rows {
+		if slices.Contains(keep, row.ID) {
+			n.rows = append(n.rows, row)
+			n.index(row)
+		}
+	}
+	n.targets = slices.DeleteFunc(n.targets, func(id int) bool { return !slices.Contains(keep, id) })
+	n.buildIndexes()
+}
+
+// collisions fails when two Pokémon have names with the same key, since
+// the key could only ever guess one of them.
+func (n *NameIndex) collisions() error {
+	idsByKey := make(map[string][]int)
+	for _, row := range n.rows {
+		for _, name := range row.Names {
+			k := normalizeKey(name)
+			if k != "" && !slices.Contains(idsByKey[k], row.ID) {
+				idsByKey[k] = append(idsByKey[k], row.ID)
+			}
+		}
+	}
+
+	var errs []error
+	for _, k := range slices.Sorted(maps.Keys(idsByKey)) {
+		ids := idsByKey[k]
+		if len(ids) < 2 {
+			continue
+		}
+		var who []string
+		for _, id := range ids {
+			who = append(who, fmt.Sprintf("%d (%s)", id, n.enById[id]))
+		}
+		errs = append(errs, fmt.Errorf("names of %s all normalize to %q", strings.Join(who, ", "), k))
+	}
+	return errors.Join(errs...)
+}
+
+// readCSV returns the header and data rows of a CSV file whose header
+// starts with the given columns. Row numbers in later errors are line
+// numbers in the file.
+func readCSV(path string, header []string) ([]string, [][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("%s: empty file", path)
+	}
+	if len(records[0]) < len(header) || !slices.Equal(records[0][:len(header)], header) {
+		return nil, nil, fmt.Errorf("%s: header is %q, want %q", path, strings.Join(records[0], ","), strings.Join(header, ","))
+	}
+	if len(records) < 2 {
+		return nil, nil, fmt.Errorf("%s: no rows", path)
+	}
+	return records[0], records[1:], nil
+}
+
+// languageColumns checks the language codes of a names header.
+func languageColumns(path string, langs []string) error {
+	seen := make(map[string]bool)
+	for _, lang := range langs {
+		if strings.TrimSpace(lang) == "" {
+			return fmt.Errorf("%s: empty language column", path)
+		}
+		if seen[lang] {
+			return fmt.Errorf("%s: duplicate language column %q", path, lang)
+		}
+		seen[lang] = true
+	}
+	return nil
+}
+
+// namesFromRow keeps the non-empty names of a row, keyed by language.
+func namesFromRow(langs []string, cells []string) map[string]string {
+	names := make(map[string]string, len(langs))
+	for j, lang := range langs {
+		if name := strings.TrimSpace(cells[j]); name != "" {
+			names[lang] = name
+		}
+	}
+	return names
+}
+
+// rowError formats a problem on the i-th data row returned by readCSV.
+func rowError(path string, i int, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", path, i+2, fmt.Sprintf(format, args...))
+}
+
+func parseID(path string, i int, row []string, seen map[int]bool) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(row[0]))
+	if err != nil || id <= 0 {
+		return 0, rowError(path, i, "invalid id %q", row[0])
+	}
+	if seen[id] {
+		return 0, rowError(path, i, "duplicate id %d", id)
+	}
+	seen[id] = true
+	return id, nil
+}
+
+func parseInt(path string, i int, column, value string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, rowError(path, i, "invalid %s %q", column, value)
+	}
+	return v, nil
+}
+
+func loadEvolutionData(path string) (map[int]EvolutionData, error) {
+	_, rows, err := readCSV(path, []string{"id", "position", "is_fully_evolved"})
+	if err != nil {
+		return nil, err
+	}
+
+	evoData := make(map[int]EvolutionData)
+	seen := make(map[int]bool)
+	var errs []error
+
+	for i, row := range rows {
+		if len(row) < 3 {
+			errs = append(errs, rowError(path, i, "expected 3 columns, got %d", len(row)))
+			continue
+		}
+		id, err1 := parseID(path, i, row, seen)
+		pos, err2 := parseInt(path, i, "position", row[1])
+		evo, err3 := parseInt(path, i, "is_fully_evolved", row[2])
+		if err := errors.Join(err1, err2, err3); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		evoData[id] = EvolutionData{Position: pos, IsFullyEvolved: evo}
+	}
+
+	return evoData, errors.Join(errs...)
+}
+
+func loadGenerationMap(path string) (map[int]int, error) {
+	_, rows, err := readCSV(path, []string{"id", "gen"})
+	if err != nil {
+		return nil, err
+	}
+
+	genMap := make(map[int]int)
+	seen := make(map[int]bool)
+	var errs []error
+
+	for i, row := range rows {
+		if len(row) < 2 {
+			errs = append(errs, rowError(path, i, "expected 2 columns, got %d", len(row)))
+			continue
+		}
+		id, err1 := parseID(path, i, row, seen)
+		gen, err2 := parseInt(path, i, "gen", row[1])
+		if err := errors.Join(err1, err2); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		genMap[id] = gen
+	}
+
+	return genMap, errors.Join(errs...)
+}
+
+// loadNames reads the names CSV. Every column after id is a language code,
+// en first; an empty cell means PokeAPI has no name in that language.
+func loadNames(csvPath string) (*NameIndex, error) {
+	header, rows, err := readCSV(csvPath, []string{"id", "en"})
+	if err != nil {
+		return nil, err
+	}
+	langs := header[1:]
+	if err := languageColumns(csvPath, langs); err != nil {
+		return nil, err
+	}
+
+	idx := &NameIndex{
+		idByKey: make(map[string]int),
+		aliases: make(map[string]int),
+		enById:  make(map[int]string),
+		langs:   langs,
+	}
+	seen := make(map[int]bool)
+	var errs []error
+
+	for i, row := range rows {
+		if len(row) < len(header) {
+			errs = append(errs, rowError(csvPath, i, "expected %d columns, got %d", len(header), len(row)))
+			continue
+		}
+		id, err := parseID(csvPath, i, row, seen)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		nr := NamesRow{ID: id, Names: namesFromRow(langs, row[1:])}
+		if nr.Names["en"] == "" {
+			errs = append(errs, rowError(csvPath, i, "empty english name"))
+			continue
+		}
+
+		idx.rows = append(idx.rows, nr)
+		idx.targets = append(idx.targets, id)
+		idx.index(nr)
+	}
+	return idx, errors.Join(errs...)
+}
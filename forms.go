@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// FormRow is a regional form from pokemon_forms.csv. Forms use PokeAPI
+// pokemon IDs (10001+) and carry their own gen and evolution position, which
+// win over any row for the same ID in pokemon_id_gen.csv or
+// pokemon_evolution_data.csv.
+type FormRow struct {
+	NamesRow
+	Gen            int
+	Position       int
+	IsFullyEvolved int
+	// blank lists the columns left empty, for validate.
+	blank []string
+}
+
+// formColumns end the forms header, after the language columns.
+var formColumns = []string{"gen", "position", "is_fully_evolved"}
+
+func loadForms(path string) ([]FormRow, error) {
+	header, rows, err := readCSV(path, []string{"id", "en"})
+	if err != nil {
+		return nil, err
+	}
+	nLangs := len(header) - 1 - len(formColumns)
+	if nLangs < 1 || !slices.Equal(header[1+nLangs:], formColumns) {
+		return nil, fmt.Errorf("%s: header must end with %q", path, strings.Join(formColumns, ","))
+	}
+	langs := header[1 : 1+nLangs]
+	if err := languageColumns(path, langs); err != nil {
+		return nil, err
+	}
+
+	var forms []FormRow
+	seen := make(map[int]bool)
+	var errs []error
+
+	for i, row := range rows {
+		if len(row) < len(header) {
+			errs = append(errs, rowError(path, i, "expected %d columns, got %d", len(header), len(row)))
+			continue
+		}
+		id, err := parseID(path, i, row, seen)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// gen and position may be left empty when the script could not
+		// resolve them; they count as 0 like any unknown ID.
+		names := namesFromRow(langs, row[1:])
+		if names["en"] == "" {
+			errs = append(errs, rowError(path, i, "empty english name"))
+			continue
+		}
+		var ints [3]int
+		var blank []string
+		for j, col := range formColumns {
+			cell := row[1+nLangs+j]
+			if strings.TrimSpace(cell) == "" {
+				blank = append(blank, col)
+				continue
+			}
+			if ints[j], err = parseInt(path, i, col, cell); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		forms = append(forms, FormRow{
+			NamesRow:       NamesRow{ID: id, Names: names},
+			Gen:            ints[0],
+			Position:       ints[1],
+			IsFullyEvolved: ints[2],
+			blank:          blank,
+		})
+	}
+	return forms, errors.Join(errs...)
+}
+
+// addForms makes the forms guessable and suggestible. The forms file is
+// authoritative for form IDs: a form that is already listed in the names
+// CSV keeps its position and the forms file names replace its names, language
+// by language. Languages only found in the forms file are suggested after the
+// names CSV ones. Forms are daily target candidates only when asTargets is
+// set.
+func (n *NameIndex) addForms(forms []FormRow, asTargets bool) {
+	rowByID := make(map[int]int, len(n.rows))
+	for i, row := range n.rows {
+		rowByID[row.ID] = i
+	}
+	isForm := make(map[int]bool, len(forms))
+
+	for _, f := range forms {
+		isForm[f.ID] = true
+		for _, lang := range slices.Sorted(maps.Keys(f.Names)) {
+			if !slices.Contains(n.langs, lang) {
+				n.langs = append(n.langs, lang)
+			}
+		}
+
+		row := f.NamesRow
+		if i, ok := rowByID[f.ID]; ok {
+			merged := maps.Clone(n.rows[i].Names)
+			maps.Copy(merged, f.Names)
+			row = NamesRow{ID: f.ID, Names: merged}
+			n.rows[i] = row
+		} else {
+			n.rows = append(n.rows, row)
+			n.targets = append(n.targets, f.ID)
+		}
+		n.index(row)
+	}
+
+	if asTargets {
+		return
+	}
+	targets := n.targets[:0]
+	for _, id := range n.targets {
+		if !isForm[id] {
+			targets = append(targets, id)
+		}
+	}
+	n.targets = targets
+}
+
+func applyForms(forms []FormRow, genMap map[int]int, evoMap map[int]EvolutionData) {
+	for _, f := range forms {
+		genMap[f.ID] = f.Gen
+		evoMap[f.ID] = EvolutionData{Position: f.Position, IsFullyEvolved: f.IsFullyEvolved}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCandidates is how many "did you mean" Pokémon a wrong guess returns.
+const maxCandidates = 3
+
+// Candidate is a Pokémon close to a misspelled guess, with its names in
+// every language.
+type Candidate struct {
+	Names    map[string]string `json:"names"`
+	Distance int               `json:"distance"`
+}
+
+// bkNode is a BK-tree over normalized keys: every child sits at its edit
+// distance from the parent, so a search only visits the children within
+// the triangle inequality.
+type bkNode struct {
+	runes    []rune
+	items    []suggestion
+	children map[int]*bkNode
+}
+
+// fuzzyMatch is a name found by the BK-tree and its distance to the query.
+type fuzzyMatch struct {
+	suggestion
+	dist int
+}
+
+func (t *bkNode) add(runes []rune, s suggestion) {
+	node := t
+	for {
+		d := editDistance(runes, node.runes)
+		if d == 0 {
+			if !slices.Contains(node.items, s) {
+				node.items = append(node.items, s)
+			}
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{runes: runes, items: []suggestion{s}}
+			return
+		}
+		node = child
+	}
+}
+
+func (t *bkNode) search(runes []rune, maxDist int, out []fuzzyMatch) []fuzzyMatch {
+	d := editDistance(runes, t.runes)
+	if d <= maxDist {
+		for _, s := range t.items {
+			out = append(out, fuzzyMatch{suggestion: s, dist: d})
+		}
+	}
+	for cd, child := range t.children {
+		if cd >= d-maxDist && cd <= d+maxDist {
+			out = child.search(runes, maxDist, out)
+		}
+	}
+	return out
+}
+
+// editDistance is the Damerau–Levenshtein distance (optimal string
+// alignment) between two keys, so "pikachu" is one edit from "pikahcu".
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// fuzzyTolerance is the number of edits allowed for a query: one for
+// short names, up to three for long ones.
+func fuzzyTolerance(q string) int {
+	switch n := utf8.RuneCountInString(q); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// buildBKTree indexes the same keys as the trie.
+func (n *NameIndex) buildBKTree() {
+	n.bk = nil
+	for _, row := range n.rows {
+		for _, lang := range n.langs {
+			name, ok := row.Names[lang]
+			if !ok {
+				continue
+			}
+			s := suggestion{id: row.ID, lang: lang, name: name}
+			for _, key := range append([]string{normalizeKey(name)}, romajiKeys(name)...) {
+				if key == "" {
+					continue
+				}
+				if n.bk == nil {
+					n.bk = &bkNode{runes: []rune(key), items: []suggestion{s}}
+					continue
+				}
+				n.bk.add([]rune(key), s)
+			}
+		}
+	}
+}
+
+// fuzzy returns the names within fuzzyTolerance edits of the normalized
+// query q, closest first, then in lang, then shortest.
+func (n *NameIndex) fuzzy(q, lang string) []fuzzyMatch {
+	if n.bk == nil || q == "" {
+		return nil
+	}
+	items := n.bk.search([]rune(q), fuzzyTolerance(q), nil)
+	slices.SortFunc(items, func(a, b fuzzyMatch) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		if (a.lang == lang) != (b.lang == lang) {
+			if a.lang == lang {
+				return -1
+			}
+			return 1
+		}
+		if d := utf8.RuneCountInString(a.name) - utf8.RuneCountInString(b.name); d != 0 {
+			return d
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	return items
+}
+
+// candidates returns the Pokémon closest to a guess that matched no name.
+func (n *NameIndex) candidates(q string) []Candidate {
+	var out []Candidate
+	seen := make(map[int]bool)
+	for _, item := range n.fuzzy(q, "") {
+		if seen[item.id] {
+			continue
+		}
+		seen[item.id] = true
+		out = append(out, Candidate{Names: n.byID[item.id].Names, Distance: item.dist})
+		if len(out) == maxCandidates {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Japanese names are keyed in hiragana, so a katakana and a hiragana
+// spelling of the same name match, and each kana name is also indexed
+// under its Hepburn romaji.
+
+// foldKana maps katakana to hiragana. normalizeKey runs it after NFKC,
+// which has already turned half-width katakana into full-width.
+func foldKana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+func isKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana)
+}
+
+var kanaRomaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// smallKana combine with the kana before them: きゃ is "kya", ファ is "fa".
+func isSmallKana(r rune) bool {
+	return strings.ContainsRune("ぁぃぅぇぉゃゅょゎ", r)
+}
+
+// romaji transliterates a key made by normalizeKey. ok is false when the
+// key has no kana, or has characters such as kanji it cannot transliterate.
+func romaji(key string) (s string, ok bool) {
+	runes := []rune(key)
+	var b strings.Builder
+	geminate := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == 'っ':
+			geminate = true
+			continue
+		case r == 'ー':
+			// The long vowel mark repeats the previous vowel.
+			if out := b.String(); out != "" && strings.ContainsRune("aeiou", rune(out[len(out)-1])) {
+				b.WriteByte(out[len(out)-1])
+			}
+			continue
+		case isKana(r):
+			ok = true
+		case unicode.Is(unicode.Han, r):
+			return "", false
+		}
+
+		syl, known := kanaRomaji[r]
+		if !known {
+			if isKana(r) {
+				return "", false
+			}
+			syl = string(r)
+		}
+		if i+1 < len(runes) && isSmallKana(runes[i+1]) && !isSmallKana(r) {
+			syl = combineKana(syl, kanaRomaji[runes[i+1]])
+			i++
+		}
+		if geminate && syl != "" {
+			if strings.HasPrefix(syl, "ch") {
+				b.WriteByte('t')
+			} else {
+				b.WriteByte(syl[0])
+			}
+			geminate = false
+		}
+		b.WriteString(syl)
+	}
+	return b.String(), ok
+}
+
+// combineKana joins a syllable and the small kana after it.
+func combineKana(syl, small string) string {
+	switch {
+	case syl == "u":
+		return "w" + small[len(small)-1:]
+	case syl == "i":
+		return "y" + small[len(small)-1:]
+	case strings.HasPrefix(small, "y") && (strings.HasSuffix(syl, "hi") || syl == "ji"):
+		// しゃ is "sha", ちゃ "cha", じゃ "ja".
+		return syl[:len(syl)-1] + small[1:]
+	default:
+		// きゃ is "kya", ファ "fa", ティ "ti".
+		return syl[:len(syl)-1] + small
+	}
+}
+
+// romajiKeys returns the romaji a player may type for a name: the full
+// transliteration and, when it differs, one with long vowels shortened, so
+// ピカチュウ answers to both "pikachuu" and "pikachu".
+func romajiKeys(name string) []string {
+	full, ok := romaji(normalizeKey(name))
+	if !ok {
+		return nil
+	}
+	short := full
+	for _, long := range []string{"aa", "ii", "uu", "ee", "oo", "ou"} {
+		short = strings.ReplaceAll(short, long, long[:1])
+	}
+	if short == full {
+		return []string{full}
+	}
+	return []string{full, short}
+}
@@ -1,24 +1,26 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"math"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 	"bufio"
 
 	"golang.org/x/text/unicode/norm"
+
+	"pokedle/pokeapi"
 )
 
 var isDevMode bool
@@ -78,13 +80,12 @@ type PokemonDetail struct {
 }
 
 
+// NamesRow holds the names of one Pokémon keyed by language code. "en" is
+// always set; pokedle-data writes the English name for any language PokeAPI
+// has no name in, and an empty cell leaves the language out.
 type NamesRow struct {
-	ID int
-	EN string
-	FR string
-	DE string
-	ES string
-	IT string
+	ID    int
+	Names map[string]string
 }
 
 type EvolutionData struct {
@@ -94,17 +95,59 @@ type EvolutionData struct {
 
 type NameIndex struct {
 	idByKey map[string]int
+	// aliases are romaji keys of kana names, only used when no name has
+	// the key.
+	aliases map[string]int
 	enById  map[int]string
 	rows    []NamesRow
+	targets []int
+	// langs is the column order of the names CSV, en first.
+	langs []string
+	byID  map[int]NamesRow
+	trie  *trieNode
+	bk    *bkNode
+}
+
+// index makes every name of row guessable.
+func (n *NameIndex) index(row NamesRow) {
+	n.enById[row.ID] = row.Names["en"]
+	for _, name := range row.Names {
+		if k := normalizeKey(name); k != "" {
+			n.idByKey[k] = row.ID
+		}
+		for _, k := range romajiKeys(name) {
+			if _, taken := n.aliases[k]; !taken {
+				n.aliases[k] = row.ID
+			}
+		}
+	}
+}
+
+// lookup returns the Pokémon a normalized key names.
+func (n *NameIndex) lookup(key string) (int, bool) {
+	if id, ok := n.idByKey[key]; ok {
+		return id, true
+	}
+	id, ok := n.aliases[key]
+	return id, ok
 }
 
 type SuggestReq struct {
 	Query string `json:"query"`
+	// Lang is the player's preferred language, ranked first.
+	Lang  string `json:"lang"`
+	Limit int    `json:"limit"`
+	// Fuzzy adds names a few typos away once the prefix matches run out.
+	Fuzzy bool `json:"fuzzy"`
+	// Guessed is what to do with Pokémon already guessed today: "flag"
+	// lists them in SuggestionGroup.Guessed, "exclude" leaves them out.
+	Guessed string `json:"guessed"`
 }
 
 type SuggestionGroup struct {
   Lang  string   `json:"lang"`
   Names []string `json:"names"`
+  Guessed []string `json:"guessed,omitempty"`
 }
 
 
@@ -126,176 +169,98 @@ func (s *Server) handleSuggest(w http.ResponseWriter, r *http.Request) {
     return
   }
 
-  groupsMap := map[string][]string{
-    "en": {},
-    "fr": {},
-    "de": {},
-    "es": {},
-    "it": {},
+  limit := req.Limit
+  if limit <= 0 {
+    limit = defaultSuggestLimit
   }
+  limit = min(limit, maxSuggestLimit)
+
+  // Groups follow the ranking: a language comes where its best name does.
+  groups := []SuggestionGroup{}
+  groupOf := make(map[string]int)
 
-  for _, row := range s.names.rows {
-    if strings.HasPrefix(normalizeKey(row.EN), q) {
-      groupsMap["en"] = append(groupsMap["en"], row.EN)
+  var guessed, skip map[int]bool
+  if req.Guessed == "flag" || req.Guessed == "exclude" {
+    p, err := s.puzzle(r)
+    if err != nil {
+      http.Error(w, err.Error(), http.StatusBadRequest)
+      return
     }
-    if strings.HasPrefix(normalizeKey(row.FR), q) {
-      groupsMap["fr"] = append(groupsMap["fr"], row.FR)
+    _, state := s.sessions.open(r, p.key())
+    guessed = make(map[int]bool, len(state.Guesses))
+    for _, id := range state.Guesses {
+      guessed[id] = true
     }
-    if strings.HasPrefix(normalizeKey(row.DE), q) {
-      groupsMap["de"] = append(groupsMap["de"], row.DE)
+    if req.Guessed == "exclude" {
+      skip = guessed
     }
-    if strings.HasPrefix(normalizeKey(row.ES), q) {
-      groupsMap["es"] = append(groupsMap["es"], row.ES)
+  }
+
+  names := s.dataset().names
+  sugs := names.suggest(q, req.Lang, limit, skip)
+  if req.Fuzzy && len(sugs) < limit {
+    seen := make(map[string]bool)
+    for _, sug := range sugs {
+      seen[sug.name] = true
     }
-    if strings.HasPrefix(normalizeKey(row.IT), q) {
-      groupsMap["it"] = append(groupsMap["it"], row.IT)
+    for _, m := range names.fuzzy(q, req.Lang) {
+      if len(sugs) == limit {
+        break
+      }
+      if !seen[m.name] && !skip[m.id] {
+        seen[m.name] = true
+        sugs = append(sugs, m.suggestion)
+      }
     }
   }
 
-  langOrder := []string{"en", "fr", "de", "es", "it"}
-  groups := []SuggestionGroup{}
-
-  for _, lang := range langOrder {
-    if names, ok := groupsMap[lang]; ok && len(names) > 0 {
-      groups = append(groups, SuggestionGroup{Lang: lang, Names: names})
+  for _, sug := range sugs {
+    i, ok := groupOf[sug.lang]
+    if !ok {
+      i = len(groups)
+      groupOf[sug.lang] = i
+      groups = append(groups, SuggestionGroup{Lang: sug.lang})
+    }
+    groups[i].Names = append(groups[i].Names, sug.name)
+    if guessed[sug.id] {
+      groups[i].Guessed = append(groups[i].Guessed, sug.name)
     }
   }
 
   writeJSON(w, groups)
 }
 
+// removeAccents strips combining marks, except the kana voicing marks that
+// tell が from か.
 func removeAccents(s string) string {
 	decomposed := norm.NFD.String(s)
 	var result []rune
 	for _, r := range decomposed {
-		if !unicode.Is(unicode.Mn, r) {
+		if !unicode.Is(unicode.Mn, r) || r == '\u3099' || r == '\u309A' {
 			result = append(result, r)
 		}
 	}
-	return string(result)
-}
-
-func normalizeKey(s string) string {
-	return strings.ToLower(removeAccents(strings.TrimSpace(s)))
-}
-
-func loadEvolutionData(path string) (map[int]EvolutionData, error) {
-    file, err := os.Open(path)
-    if err != nil {
-        return nil, err
-    }
-    defer file.Close()
-
-    reader := csv.NewReader(file)
-    rows, err := reader.ReadAll()
-    if err != nil {
-        return nil, err
-    }
-
-    evoData := make(map[int]EvolutionData)
-
-    for _, row := range rows[1:] {
-        id, err1 := strconv.Atoi(row[0])
-        pos, err2 := strconv.Atoi(row[1])
-        evo, err3 := strconv.Atoi(row[2])
-        if err1 != nil || err2 != nil || err3 != nil {
-            continue
-        }
-        evoData[id] = EvolutionData{Position: pos, IsFullyEvolved: evo}
-    }
-
-    return evoData, nil
-}
-
-
-func loadGenerationMap(path string) (map[int]int, error) {
-    file, err := os.Open(path)
-    if err != nil {
-        return nil, err
-    }
-    defer file.Close()
-
-    reader := csv.NewReader(file)
-    rows, err := reader.ReadAll()
-    if err != nil {
-        return nil, err
-    }
-
-    genMap := make(map[int]int)
-
-    for _, row := range rows[1:] {
-        id, err1 := strconv.Atoi(row[0])
-        gen, err2 := strconv.Atoi(row[1])
-        if err1 != nil || err2 != nil {
-            continue
-        }
-        genMap[id] = gen
-    }
-
-    return genMap, nil
+	return norm.NFC.String(string(result))
 }
 
+// genderSigns spell out the symbols of Nidoran♀ and Nidoran♂.
+var genderSigns = strings.NewReplacer("♀", "f", "♂", "m")
 
-func loadNames(csvPath string) (*NameIndex, error) {
-	f, err := os.Open(csvPath)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	r := csv.NewReader(f)
-	records, err := r.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-	if len(records) < 2 {
-		return nil, fmt.Errorf("csv has no rows")
-	}
-
-	idx := &NameIndex{
-		idByKey: make(map[string]int),
-		enById:  make(map[int]string),
-	}
-	for i, row := range records {
-		if i == 0 {
-			continue
-		}
-		if len(row) < 6 {
-			continue
-		}
-		id, err := strconv.Atoi(strings.TrimSpace(row[0]))
-		if err != nil {
-			continue
-		}
-		nr := NamesRow{
-			ID: id,
-			EN: row[1],
-			FR: row[2],
-			DE: row[3],
-			ES: row[4],
-			IT: row[5],
-		}
-		
-		idx.rows = append(idx.rows, nr)
-		idx.enById[id] = nr.EN
-		for _, name := range row[1:6] {
-			k := normalizeKey(name)
-			if k != "" {
-				idx.idByKey[k] = id
-			}
+// normalizeKey folds full-width and half-width forms (NFKC), katakana into
+// hiragana, accents and case, and keeps only letters and digits, so
+// "Mr. Mime", "mr mime" and "MrMime" share a key.
+func normalizeKey(s string) string {
+	s = norm.NFKC.String(genderSigns.Replace(s))
+	s = strings.ToLower(removeAccents(foldKana(s)))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '\u3099' || r == '\u309A' {
+			return r
 		}
-	}
-	return idx, nil
+		return -1
+	}, s)
 }
 
-func (n *NameIndex) maxIndex() int { return len(n.rows) }
-
-func (n *NameIndex) idAt(i int) int {
-	if i < 0 || i >= len(n.rows) {
-		return 0
-	}
-	return n.rows[i].ID
-}
+func (n *NameIndex) maxIndex() int { return len(n.targets) }
 
 
 func dayKey(t time.Time) string {
@@ -304,6 +269,9 @@ func dayKey(t time.Time) string {
 
 func loadEnvKey(filename, key string) string {
 	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return ""
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -323,59 +291,83 @@ func loadEnvKey(filename, key string) string {
 	return ""
 }
 
-func pickDailyIndex(names *NameIndex, t time.Time) int {
-	if names.maxIndex() == 0 {
-		return 0
-	}
+type Server struct {
+	data            atomic.Pointer[Dataset]
+	regionalTargets bool
+	loadSource      sourceLoader
+	api             *pokeapi.Client
+	dataDir         string
+	staticFS        http.Handler
+	sessions        *SessionStore
+	// maxGuesses ends the day as lost after that many misses; 0 is no cap.
+	maxGuesses int
+	// epoch is the first day of the schedule and of the archive.
+	epoch    time.Time
+	schedule *Schedule
+
+	pinMu     sync.Mutex
+	pinnedDay string
+	pinnedID  int
+}
+
+func NewServer() (*Server, error) {
+	wd, _ := os.Getwd()
+	dataDir := filepath.Join(wd, "data")
 
-	secret := loadEnvKey(".env", "POKEDLE_SECRET")
-	msg := []byte(dayKey(t))
-	var sum []byte
-	if secret != "" {
-		m := hmac.New(sha256.New, []byte(secret))
-		m.Write(msg)
-		sum = m.Sum(nil)
-	} else {
-		h := sha256.Sum256(msg)
-		sum = h[:]
+	s := &Server{
+		regionalTargets: os.Getenv("POKEDLE_REGIONAL_TARGETS") != "0",
+		dataDir:         dataDir,
+		staticFS:        http.FileServer(http.Dir(filepath.Join(wd, "static"))),
 	}
 
-	var v uint64 = 0
-	for i := 0; i < 8; i++ {
-		v = (v << 8) | uint64(sum[i])
+	var err error
+	s.api = pokeapi.NewClient(pokeapi.ConfigFromEnv())
+	if s.loadSource, err = newSource(s.api, filepath.Join(dataDir, catalogFile)); err != nil {
+		return nil, err
+	}
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("invalid dataset in %s:\n%w", dataDir, err)
 	}
-	return int(v % uint64(names.maxIndex()))
-}
-
-
-type Server struct {
-	names    *NameIndex
-	csvPath  string
-	dataDir  string
-	staticFS http.Handler
-}
 
-func must[T any](v T, err error) T {
+	stateDir := os.Getenv("POKEDLE_STATE_DIR")
+	if stateDir == "" {
+		stateDir = filepath.Join(wd, "state")
+	} else if stateDir == "off" {
+		stateDir = ""
+	}
+	secret := loadEnvKey(".env", "POKEDLE_SECRET")
+	sessions, err := newSessionStore(stateDir, secret)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("loading sessions: %w", err)
 	}
-	return v
-}
-
-func NewServer() *Server {
-	wd, _ := os.Getwd()
-	dataDir := filepath.Join(wd, "data")
-	csvPath := filepath.Join(dataDir, "pokemon_names_multilang.csv")
+	s.sessions = sessions
+	if s.maxGuesses, err = guessLimit(); err != nil {
+		return nil, err
+	}
+	if s.epoch, err = loadEpoch(); err != nil {
+		return nil, err
+	}
+	s.schedule = newSchedule(secret, s.epoch)
 
-	names := must(loadNames(csvPath))
-	staticFS := http.FileServer(http.Dir(filepath.Join(wd, "static")))
+	return s, nil
+}
 
-	return &Server{
-		names:    names,
-		csvPath:  csvPath,
-		dataDir:  dataDir,
-		staticFS: staticFS,
+// guessLimit reads the guess cap of the current mode: POKEDLE_MAX_GUESSES,
+// or POKEDLE_DEV_MAX_GUESSES in dev mode when it is set.
+func guessLimit() (int, error) {
+	name := "POKEDLE_MAX_GUESSES"
+	if isDevMode && os.Getenv("POKEDLE_DEV_MAX_GUESSES") != "" {
+		name = "POKEDLE_DEV_MAX_GUESSES"
+	}
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
 	}
+	return n, nil
 }
 
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
@@ -398,13 +390,30 @@ type GuessResp struct {
 	Guess   		map[string]any    `json:"guess"`
 	Hints   		map[string]any    `json:"hints"`
 	Reveal  		map[string]any    `json:"reveal,omitempty"`
+	// Outcome is "won" or "lost" once the guess ends the day.
+	Outcome			string			  `json:"outcome,omitempty"`
 	GuessCounter	int				  `json:"guessCounter"`
+	MaxGuesses		int				  `json:"maxGuesses,omitempty"`
+	// Candidates are the closest names to a guess that matched none.
+	Candidates		[]Candidate		  `json:"candidates,omitempty"`
+	// Duplicate is set when the Pokémon was already guessed today; the
+	// guess is not counted.
+	Duplicate		bool			  `json:"duplicate,omitempty"`
 }
 
 func (s *Server) handleGuess(w http.ResponseWriter, r *http.Request) {
-	cookie, err := r.Cookie("solved")
-	if err == nil && cookie.Value == "true" {
-		writeJSON(w, GuessResp{OK: false, Error: "You already found. Try tomorrow!", Correct: true})
+	p, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sid, state := s.sessions.open(r, p.key())
+	if state.Solved {
+		writeJSON(w, GuessResp{OK: false, Error: p.doneMessage("You already found."), Correct: true})
+		return
+	}
+	if state.Lost {
+		writeJSON(w, GuessResp{OK: false, Error: p.doneMessage("No guesses left."), Outcome: "lost", GuessCounter: len(state.Guesses), MaxGuesses: s.maxGuesses})
 		return
 	}
 	
@@ -418,50 +427,131 @@ func (s *Server) handleGuess(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "bad json", http.StatusBadRequest)
 		return
 	}
+	data := s.dataset()
 	key := normalizeKey(req.Guess)
-	id, ok := s.names.idByKey[key]
+	id, ok := data.names.lookup(key)
 	if !ok {
-		writeJSON(w, GuessResp{OK: false, Error: "Incorrect Pokémon name", Correct: false})
+		writeJSON(w, GuessResp{OK: false, Error: "Incorrect Pokémon name", Correct: false, Candidates: data.names.candidates(key)})
 		return
 	}
-	pokeName := s.names.enById[id]
-
-
-	cookie, err = r.Cookie("guesses")
-	var guessCount int
-	if err == nil {
-		guessCount, _ = strconv.Atoi(cookie.Value)
+	if slices.Contains(state.Guesses, id) {
+		writeJSON(w, GuessResp{OK: false, Error: "Already guessed today", Correct: false, Duplicate: true, GuessCounter: len(state.Guesses)})
+		return
 	}
-	guessCount++
-
-	todayIdx := pickDailyIndex(s.names, time.Now().UTC())
-	targetID := s.names.idAt(todayIdx)
-
-	guessP, gErr := fetchPokemon(id)
-	targetP, tErr := fetchPokemon(targetID)
-	if gErr != nil || tErr != nil {
+	targetID := s.targetID(p.t)
+	entry, err := s.compare(data, id, targetID)
+	if err != nil {
 		writeJSON(w, GuessResp{OK: false, Error: "PokeAPI Error", Correct: false})
 		return
 	}
+	if sid == "" {
+		// Only a guess starts a session; reading the day leaves none.
+		sid = s.sessions.start(w, r, p.key())
+	}
+	// A state restored from the state cookie has guesses but no board yet;
+	// fill it first so the new entry lands after them.
+	if _, err := s.fillHistory(w, sid, p, state); err != nil {
+		writeJSON(w, GuessResp{OK: false, Error: "PokeAPI Error", Correct: false})
+		return
+	}
+	resp := GuessResp{
+		OK:      true,
+		Correct: entry.Correct,
+		Guess:   entry.Guess,
+		Hints:   entry.Hints,
+	}
+
+	locked := false
+	state = s.sessions.update(w, sid, p.key(), func(ds *DayState) {
+		// A concurrent request may have ended the day or counted the
+		// same guess since the checks above.
+		if ds.over() || (s.maxGuesses > 0 && len(ds.Guesses) >= s.maxGuesses) {
+			locked = true
+			return
+		}
+		if slices.Contains(ds.Guesses, id) {
+			return
+		}
+		// A history still missing earlier entries is completed by
+		// fillHistory, new guess included.
+		if len(ds.History) == len(ds.Guesses) {
+			ds.History = append(ds.History, entry)
+		}
+		ds.Guesses = append(ds.Guesses, id)
+		ds.HintTier = hintTier(len(ds.Guesses))
+		ds.Solved = ds.Solved || resp.Correct
+		ds.Lost = !ds.Solved && s.maxGuesses > 0 && len(ds.Guesses) >= s.maxGuesses
+	})
+	if locked {
+		if state.Solved {
+			writeJSON(w, GuessResp{OK: false, Error: p.doneMessage("You already found."), Correct: true})
+		} else {
+			writeJSON(w, GuessResp{OK: false, Error: p.doneMessage("No guesses left."), Outcome: "lost", GuessCounter: len(state.Guesses), MaxGuesses: s.maxGuesses})
+		}
+		return
+	}
+	resp.GuessCounter = len(state.Guesses)
+	resp.MaxGuesses = s.maxGuesses
 
-	evoMap, err := loadEvolutionData("data/pokemon_evolution_data.csv")
+	switch {
+	case state.Solved:
+		resp.Outcome = "won"
+	case state.Lost:
+		resp.Outcome = "lost"
+	}
+	if resp.Outcome != "" {
+		resp.Reveal = s.reveal(data, targetID)
+		s.sessions.record(sid, p.key(), state.Solved, len(state.Guesses))
+	}
+
+	writeJSON(w, resp)
+}
+
+// reveal describes the target once the day is over.
+func (s *Server) reveal(data *Dataset, targetID int) map[string]any {
+	targetP, err := data.source.Pokemon(targetID)
 	if err != nil {
-		panic(err)
+		return nil
 	}
-	
-	guessEvo := evoMap[guessP.ID]
-	targetEvo := evoMap[targetP.ID]
-	
-	guessType1, guessType2 := extractTypes(guessP)
 	targetType1, targetType2 := extractTypes(targetP)
+	targetSprite := targetP.Sprites.FrontDefault
+	if oa, ok := targetP.Sprites.Other["official-artwork"]; ok {
+		if oa.FrontDefault != "" {
+			targetSprite = oa.FrontDefault
+		}
+	}
+	return map[string]any{
+		"id":     targetP.ID,
+		"name":   targetP.Name,
+		"types":  []string{targetType1, targetType2},
+		"height": targetP.Height,
+		"weight": targetP.Weight,
+		"sprite": targetSprite,
+	}
+}
 
-	genMap, err := loadGenerationMap("data/pokemon_id_gen.csv")
-	if err != nil {
-		panic(err)
+// compare checks guess id against the target and computes the hints the
+// board shows for it.
+func (s *Server) compare(data *Dataset, id, targetID int) (GuessEntry, error) {
+	pokeName := data.names.enById[id]
+
+	guessP, gErr := data.source.Pokemon(id)
+	targetP, tErr := data.source.Pokemon(targetID)
+	if gErr != nil {
+		return GuessEntry{}, gErr
 	}
+	if tErr != nil {
+		return GuessEntry{}, tErr
+	}
+
+	guessEvo := data.evo[guessP.ID]
+	targetEvo := data.evo[targetP.ID]
 	
-	guessGen := genMap[guessP.ID]
-	targetGen := genMap[targetP.ID]
+	guessType1, guessType2 := extractTypes(guessP)
+	targetType1, targetType2 := extractTypes(targetP)
+
+	guessGen := data.gen[guessP.ID]
+	targetGen := data.gen[targetP.ID]
 	
 	weightHint := strconv.FormatFloat(float64(guessP.Weight)/10, 'f', 1, 64) + "kg"
 	switch {
@@ -485,8 +575,8 @@ func (s *Server) handleGuess(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resp := GuessResp{
-		OK:      true,
+	return GuessEntry{
+		ID:      id,
 		Correct: guessP.ID == targetP.ID,
 		Guess: map[string]any{
 			"name":   pokeName,
@@ -514,111 +604,155 @@ func (s *Server) handleGuess(w http.ResponseWriter, r *http.Request) {
 			"targetFullyEvolved": targetEvo.IsFullyEvolved,
 			"distance":   int(math.Abs(float64(targetP.ID - guessP.ID))),
 		},
-		GuessCounter: guessCount,
-	}
-	
-	now := time.Now()
-	midnight := time.Date(
-		now.Year(), now.Month(), now.Day()+1,
-		0, 0, 0, 0,
-		now.Location(),
-	)
-	if isDevMode {
-		midnight = time.Now().Add(5 * time.Minute)
-	}
-
-	if resp.Correct {
-		http.SetCookie(w, &http.Cookie{
-			Name:     "solved",
-			Value:    "true",
-			Path:     "/",
-			Expires:  midnight,
-			HttpOnly: true,
-		})
-		targetSprite := targetP.Sprites.FrontDefault
-		if oa, ok := targetP.Sprites.Other["official-artwork"]; ok {
-			if oa.FrontDefault != "" {
-				targetSprite = oa.FrontDefault
-			}
-		}
-		resp.Reveal = map[string]any{
-			"id":     targetP.ID,
-			"name":   targetP.Name,
-			"types":  []string{targetType1, targetType2},
-			"height": targetP.Height,
-			"weight": targetP.Weight,
-			"sprite": targetSprite,
-		}
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "guesses",
-		Value:    strconv.Itoa(guessCount),
-		Path:     "/",
-		Expires:  midnight,
-		HttpOnly: true,
-	})
-	
-	writeJSON(w, resp)
+	}, nil
 }
 
 func (s *Server) handleToday(w http.ResponseWriter, r *http.Request) {
-	idx := pickDailyIndex(s.names, time.Now().UTC())
-
-	cookie, err := r.Cookie("guesses")
-	var guessCount int
-	if err == nil {
-		guessCount, _ = strconv.Atoi(cookie.Value)
+	p, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	
+	data := s.dataset()
+	names := data.names
+
+	_, state := s.sessions.open(r, p.key())
+	guessCount := len(state.Guesses)
+
 	writeJSON(w, map[string]any{
-		"date":      dayKey(time.Now().UTC()),
-		"index":     idx,
-		"max":       s.names.maxIndex(),
-		"remaining": s.names.maxIndex() - idx - 1,
+		"date":      p.day,
+		"archive":   p.archive,
+		"index":     s.schedule.dayNumber(p.t),
+		"max":       names.maxIndex(),
+		"remaining": s.schedule.remaining(p.t, data.pool()),
 		"guessCounter" : guessCount,
+		"maxGuesses": s.maxGuesses,
 	})
 }
 
-func (s *Server) handleHints(w http.ResponseWriter, r *http.Request) {
-	idx := pickDailyIndex(s.names, time.Now().UTC())
-	targetID := s.names.idAt(idx)
+// handleHistory returns the day's guesses, oldest first, so the client can
+// rebuild the board and the hints after a reload.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	p, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sid, state := s.sessions.open(r, p.key())
+	state, err = s.fillHistory(w, sid, p, state)
+	if err != nil {
+		writeJSON(w, map[string]any{"ok": false, "error": "PokeAPI Error"})
+		return
+	}
+
+	history := state.History
+	if history == nil {
+		history = []GuessEntry{}
+	}
+	resp := map[string]any{
+		"ok":           true,
+		"date":         p.day,
+		"archive":      p.archive,
+		"guessCounter": len(state.Guesses),
+		"maxGuesses":   s.maxGuesses,
+		"tier":         state.HintTier,
+		"solved":       state.Solved,
+		"lost":         state.Lost,
+		"guesses":      history,
+	}
+	if state.over() {
+		resp["reveal"] = s.reveal(s.dataset(), s.targetID(p.t))
+	}
+	writeJSON(w, resp)
+}
+
+// fillHistory computes the board of the guesses a state restored from the
+// state cookie, which only carries the IDs, has no history for.
+func (s *Server) fillHistory(w http.ResponseWriter, sid string, p puzzle, state DayState) (DayState, error) {
+	if len(state.History) == len(state.Guesses) {
+		return state, nil
+	}
+	data := s.dataset()
+	targetID := s.targetID(p.t)
+	history := state.History
+	for _, id := range state.Guesses[len(history):] {
+		entry, err := s.compare(data, id, targetID)
+		if err != nil {
+			return state, err
+		}
+		history = append(history, entry)
+	}
+	if sid == "" {
+		state.History = history
+		return state, nil
+	}
+	return s.sessions.update(w, sid, p.key(), func(ds *DayState) {
+		if len(ds.Guesses) == len(history) {
+			ds.History = history
+		}
+	}), nil
+}
+
+// handleDataset reports which build of data/ the server is running and
+// whether its files still match the manifest.
+func (s *Server) handleDataset(w http.ResponseWriter, r *http.Request) {
+	data := s.dataset()
+	resp := map[string]any{
+		"version":  data.version(),
+		"verified": data.manifest != nil && len(data.modified) == 0,
+		"modified": data.modified,
+		"langs":    data.names.langs,
+	}
+	if m := data.manifest; m != nil {
+		if _, f, ok := m.Latest(); ok {
+			resp["generatedAt"] = f.GeneratedAt
+			resp["source"] = f.Source
+		}
+		resp["files"] = m.Files
+	}
+	writeJSON(w, resp)
+}
 
-	cookie, err := r.Cookie("guesses")
-	var guessCount int
-	if err == nil {
-		guessCount, _ = strconv.Atoi(cookie.Value)
+func (s *Server) handleHints(w http.ResponseWriter, r *http.Request) {
+	p, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	data := s.dataset()
+	targetID := s.targetID(p.t)
 
-	tier := guessCount / 3
+	_, state := s.sessions.open(r, p.key())
+	tier := state.HintTier
 
 	response := map[string]any{
 		"tier": tier,
 	}
 
 	if tier >= 1 {
-		p, _ := fetchPokemonDetail(targetID)
-		if p != nil && p.Cries.Latest != "" {
-			cryPath := downloadCryToStatic(targetID, p.Cries.Latest)
-			response["cry"] = cryPath
+		if url, _ := data.source.Cry(targetID); url != "" {
+			if cryPath := s.downloadCryToStatic(targetID, url); cryPath != "" {
+				response["cry"] = cryPath
+			}
 		}
 	}
 
 	if tier >= 2 {
-		p, _ := fetchPokemonDetail(targetID)
-		if p != nil && len(p.Types) > 0 {
-			var types []string
-			for _, t := range p.Types {
-				types = append(types, strings.ToUpper(t.Type.Name))
-			}
+		if types, _ := data.source.Types(targetID); len(types) > 0 {
 			response["types"] = types
 		}
 	}
 
 	if tier >= 3 {
-		descMap := fetchDescriptionsAllLanguages(targetID)
-		if len(descMap) > 0 {
-			response["description"] = descMap
+		descMap, _ := data.source.Descriptions(targetID)
+		descriptions := make(map[string]string)
+		for _, lang := range data.names.langs {
+			if desc, ok := descMap[lang]; ok {
+				descriptions[lang] = desc
+			}
+		}
+		if len(descriptions) > 0 {
+			response["description"] = descriptions
 		}
 	}
 
@@ -626,91 +760,27 @@ func (s *Server) handleHints(w http.ResponseWriter, r *http.Request) {
 }
 
 
-func fetchPokemon(id int) (*Pokemon, error) {
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%d", id)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("pokeapi status: %d", resp.StatusCode)
-	}
-	var p Pokemon
-	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
-		return nil, err
-	}
-	return &p, nil
-}
-
-func fetchPokemonDetail(id int) (*PokemonDetail, error) {
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%d/", id)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	var p PokemonDetail
-	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
-		return nil, err
-	}
-	return &p, nil
-}
-
 func cleanFlavorText(text string) string {
 	text = strings.ReplaceAll(text, "\n", " ")
 	text = strings.ReplaceAll(text, "\f", " ")
 	return strings.TrimSpace(text)
 }
 
-func fetchDescriptionsAllLanguages(id int) map[string]string {
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon-species/%d/", id)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil
-	}
-	defer resp.Body.Close()
-
-	var data SpeciesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil
-	}
-
-	allowedLangs := map[string]bool{
-		"en": true,
-		"fr": true,
-		"de": true,
-		"es": true,
-		"it": true,
-	}
-
-	descriptions := make(map[string]string)
-	for _, entry := range data.FlavorTextEntries {
-		lang := entry.Language.Name
-		if allowedLangs[lang] {
-			if _, ok := descriptions[lang]; !ok {
-				descriptions[lang] = cleanFlavorText(entry.FlavorText)
-			}
-		}
-	}
-	return descriptions
-}
-
-
-
-func downloadCryToStatic(id int, url string) string {
-	resp, err := http.Get(url)
-	if err != nil || resp.StatusCode != 200 {
-		return ""
-	}
-	defer resp.Body.Close()
+func (s *Server) downloadCryToStatic(id int, url string) string {
 	filename := fmt.Sprintf("static/hint_%d.ogg", id)
+	if _, err := os.Stat(filename); err == nil {
+		return "/" + filename
+	}
 	out, err := os.Create(filename)
 	if err != nil {
 		return ""
 	}
 	defer out.Close()
-	_, _ = io.Copy(out, resp.Body)
+	if err := s.api.Download(url, out); err != nil {
+		out.Close()
+		os.Remove(filename)
+		return ""
+	}
 	return "/" + filename
 }
 
@@ -740,16 +810,39 @@ func main() {
 	if len(os.Args) == 2 && os.Args[1] == "dev" {
 		isDevMode = true
 	}
+	if len(os.Args) == 2 && os.Args[1] == "validate" {
+		runValidate()
+		return
+	}
 
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	srv := NewServer()
+	srv, err := NewServer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	watchInterval := 10 * time.Second
+	if v := os.Getenv("POKEDLE_WATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid POKEDLE_WATCH_INTERVAL %q: %v", v, err)
+		}
+		watchInterval = d
+	}
+	go srv.watchData(watchInterval)
+	go srv.sessions.persist(sessionFlushInterval)
 
 	http.HandleFunc("/", srv.handleIndex)
 	http.Handle("/static/", http.StripPrefix("/static/", srv.staticFS))
 	http.HandleFunc("/api/guess", srv.handleGuess)
 	http.HandleFunc("/api/today", srv.handleToday)
 	http.HandleFunc("/api/hints", srv.handleHints)
+	http.HandleFunc("/api/history", srv.handleHistory)
+	http.HandleFunc("/api/stats", srv.handleStats)
+	http.HandleFunc("/api/share", srv.handleShare)
+	http.HandleFunc("/api/archive", srv.handleArchive)
 	http.HandleFunc("/api/suggest", srv.handleSuggest)
+	http.HandleFunc("/api/dataset", srv.handleDataset)
 
 
 	port := os.Getenv("PORT")
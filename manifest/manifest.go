@@ -0,0 +1,207 @@
+// Package manifest describes a build of the data/ directory: the row count
+// and SHA-256 of every file, so the server can tell a hand-edited file from
+// a built one, when and from which PokeAPI each file was generated, and
+// which Pokémon each build added or removed.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FileName is the manifest's name inside the data directory.
+const FileName = "manifest.json"
+
+type File struct {
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+	// GeneratedAt and Source are the provenance of the last build of the
+	// file. Rehashing a file keeps them; both are empty for a file that was
+	// never built since the manifest has recorded provenance.
+	GeneratedAt time.Time `json:"generated_at,omitzero"`
+	Source      string    `json:"source,omitempty"`
+}
+
+// Batch is the Pokémon IDs a build added to or removed from a file that
+// already existed. File is only set on removals.
+type Batch struct {
+	At   time.Time `json:"at"`
+	File string    `json:"file,omitempty"`
+	IDs  []int     `json:"ids"`
+}
+
+type Manifest struct {
+	// Version is derived from the file checksums, so two identical
+	// datasets always share it.
+	Version string          `json:"version"`
+	Files   map[string]File `json:"files"`
+	// Added lists the batches of Pokémon appended to the dataset, oldest
+	// first. The daily schedule only lets them into the cycles that start
+	// after their build.
+	Added []Batch `json:"added,omitempty"`
+	// Removed lists the batches of Pokémon dropped from a file, oldest
+	// first. The cycles already started keep them until their removal.
+	Removed []Batch `json:"removed,omitempty"`
+}
+
+// Load reads the manifest of dataDir. The error wraps fs.ErrNotExist when
+// there is none.
+func Load(dataDir string) (*Manifest, error) {
+	body, err := os.ReadFile(filepath.Join(dataDir, FileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", FileName, err)
+	}
+	return &m, nil
+}
+
+// Describe returns the checksum and row count of a data file: data rows
+// for a CSV, elements for a JSON array.
+func Describe(path string) (File, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, err
+	}
+	sum := sha256.Sum256(body)
+	f := File{SHA256: hex.EncodeToString(sum[:])}
+
+	switch filepath.Ext(path) {
+	case ".csv":
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		if err != nil {
+			return File{}, fmt.Errorf("%s: %w", path, err)
+		}
+		f.Rows = max(len(records)-1, 0)
+	case ".json":
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return File{}, fmt.Errorf("%s: %w", path, err)
+		}
+		f.Rows = len(items)
+	}
+	return f, nil
+}
+
+// Update describes the named files of dataDir again and recomputes
+// Version. The provenance of the files, and the entries of other files, are
+// kept as they were.
+func (m *Manifest) Update(dataDir string, names ...string) error {
+	if m.Files == nil {
+		m.Files = make(map[string]File)
+	}
+	for _, name := range names {
+		f, err := Describe(filepath.Join(dataDir, name))
+		if err != nil {
+			return err
+		}
+		f.GeneratedAt, f.Source = m.Files[name].GeneratedAt, m.Files[name].Source
+		m.Files[name] = f
+	}
+	m.Version = m.version()
+	return nil
+}
+
+// Built records that the named files of dataDir were just generated from
+// source, then describes them like Update.
+func (m *Manifest) Built(dataDir, source string, at time.Time, names ...string) error {
+	if err := m.Update(dataDir, names...); err != nil {
+		return err
+	}
+	for _, name := range names {
+		f := m.Files[name]
+		f.GeneratedAt, f.Source = at, source
+		m.Files[name] = f
+	}
+	return nil
+}
+
+// AddedAt returns when each ID appended by a build was first added.
+func (m *Manifest) AddedAt() map[int]time.Time {
+	at := make(map[int]time.Time)
+	for _, b := range m.Added {
+		for _, id := range b.IDs {
+			if _, ok := at[id]; !ok {
+				at[id] = b.At
+			}
+		}
+	}
+	return at
+}
+
+// RemovedAt returns when each ID a build dropped from the named files was
+// last removed.
+func (m *Manifest) RemovedAt(names ...string) map[int]time.Time {
+	at := make(map[int]time.Time)
+	for _, b := range m.Removed {
+		if slices.Contains(names, b.File) {
+			for _, id := range b.IDs {
+				at[id] = b.At
+			}
+		}
+	}
+	return at
+}
+
+// Latest returns the most recently built file, false when no file has a
+// known provenance.
+func (m *Manifest) Latest() (string, File, bool) {
+	var latest string
+	for name, f := range m.Files {
+		if !f.GeneratedAt.IsZero() && (latest == "" || f.GeneratedAt.After(m.Files[latest].GeneratedAt)) {
+			latest = name
+		}
+	}
+	return latest, m.Files[latest], latest != ""
+}
+
+func (m *Manifest) version() string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(m.Files)) {
+		fmt.Fprintf(h, "%s:%s\n", name, m.Files[name].SHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Verify returns the files of dataDir that are missing or no longer match
+// their checksum, with the reason.
+func (m *Manifest) Verify(dataDir string) []string {
+	var problems []string
+	for name, want := range m.Files {
+		got, err := Describe(filepath.Join(dataDir, name))
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		case got.SHA256 != want.SHA256 && want.GeneratedAt.IsZero():
+			problems = append(problems, fmt.Sprintf("%s: checksum mismatch", name))
+		case got.SHA256 != want.SHA256:
+			problems = append(problems, fmt.Sprintf("%s: checksum mismatch, modified since %s", name, want.GeneratedAt.Format(time.DateOnly)))
+		case got.Rows != want.Rows:
+			problems = append(problems, fmt.Sprintf("%s: %d rows, manifest says %d", name, got.Rows, want.Rows))
+		}
+	}
+	slices.Sort(problems)
+	return problems
+}
+
+// String is a one-line summary for logs.
+func (m *Manifest) String() string {
+	name, f, ok := m.Latest()
+	if !ok {
+		return fmt.Sprintf("version %s, provenance unknown", m.Version)
+	}
+	return fmt.Sprintf("version %s, %s generated %s from %s", m.Version, name,
+		f.GeneratedAt.Format(time.RFC3339), strings.TrimRight(f.Source, "/"))
+}
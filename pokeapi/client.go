@@ -0,0 +1,264 @@
+// Package pokeapi is the PokeAPI v2 client shared by the server and the
+// dataset scripts. Every request goes through a global rate limit, a
+// per-attempt timeout and retries with exponential backoff, and JSON
+// responses are cached in memory and optionally on disk.
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+// ErrNotFound is returned when PokeAPI answers 404 for a resource.
+var ErrNotFound = errors.New("pokeapi: not found")
+
+// StatusError is returned for any other non-200 answer once retries are
+// exhausted.
+type StatusError struct {
+	URL  string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("pokeapi status %d for %s", e.Code, e.URL)
+}
+
+type Config struct {
+	// BaseURL defaults to DefaultBaseURL. Absolute URLs returned by the API
+	// (species links, evolution chains...) are rewritten onto it.
+	BaseURL string
+	// CacheDir stores one JSON file per URL. Empty disables the disk cache.
+	CacheDir string
+	// CacheMaxAge makes older disk entries stale. Zero never expires them.
+	CacheMaxAge time.Duration
+	// CacheSize is the number of responses kept in the in-memory LRU.
+	CacheSize int
+	// Timeout bounds a single attempt, not the whole retry loop.
+	Timeout    time.Duration
+	MaxRetries int
+	// Backoff is the delay before the first retry; it doubles each time.
+	Backoff time.Duration
+	// RateLimit is the minimum delay between two requests, across all
+	// goroutines sharing the client.
+	RateLimit time.Duration
+}
+
+// ConfigFromEnv returns the default configuration, overridden by
+// POKEAPI_BASE_URL, POKEAPI_CACHE_DIR ("off" disables the disk cache) and
+// POKEAPI_CACHE_MAX_AGE (a duration such as "168h").
+func ConfigFromEnv() Config {
+	cfg := Config{
+		BaseURL:  os.Getenv("POKEAPI_BASE_URL"),
+		CacheDir: ".cache/pokeapi",
+	}
+	if dir := os.Getenv("POKEAPI_CACHE_DIR"); dir == "off" {
+		cfg.CacheDir = ""
+	} else if dir != "" {
+		cfg.CacheDir = dir
+	}
+	if age, err := time.ParseDuration(os.Getenv("POKEAPI_CACHE_MAX_AGE")); err == nil {
+		cfg.CacheMaxAge = age
+	}
+	return cfg
+}
+
+type Client struct {
+	baseURL    string
+	http       *http.Client
+	maxRetries int
+	backoff    time.Duration
+
+	mem  *lru
+	disk *diskCache
+
+	hits, fetches atomic.Int64
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = 512
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 500 * time.Millisecond
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = 50 * time.Millisecond
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		http:       &http.Client{Timeout: cfg.Timeout},
+		maxRetries: cfg.MaxRetries,
+		backoff:    cfg.Backoff,
+		mem:        newLRU(cfg.CacheSize),
+		interval:   cfg.RateLimit,
+	}
+	if cfg.CacheDir != "" {
+		c.disk = &diskCache{dir: cfg.CacheDir, maxAge: cfg.CacheMaxAge}
+	}
+	return c
+}
+
+func (c *Client) BaseURL() string { return c.baseURL }
+
+// URL resolves a path such as "pokemon/25" against the base URL. Absolute
+// PokeAPI URLs are moved onto the configured base URL; other absolute URLs
+// are returned unchanged.
+func (c *Client) URL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		if rest, ok := strings.CutPrefix(path, DefaultBaseURL); ok {
+			return c.baseURL + rest
+		}
+		return path
+	}
+	return c.baseURL + "/" + strings.TrimLeft(path, "/")
+}
+
+// Get returns the body of a PokeAPI resource, from cache when possible.
+func (c *Client) Get(path string) ([]byte, error) {
+	url := c.URL(path)
+	key := strings.TrimRight(url, "/")
+
+	if body, ok := c.mem.get(key); ok {
+		c.hits.Add(1)
+		return body, nil
+	}
+	if body, ok := c.disk.get(key); ok {
+		c.hits.Add(1)
+		c.mem.put(key, body)
+		return body, nil
+	}
+
+	c.fetches.Add(1)
+	body, err := c.fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	c.mem.put(key, body)
+	c.disk.put(key, body)
+	return body, nil
+}
+
+// Stats returns how many Get calls were answered from cache and how many
+// went to the network.
+func (c *Client) Stats() (hits, fetches int64) {
+	return c.hits.Load(), c.fetches.Load()
+}
+
+// GetJSON decodes a PokeAPI resource into v.
+func (c *Client) GetJSON(path string, v any) error {
+	body, err := c.Get(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Exists reports whether the resource answers 200.
+func (c *Client) Exists(path string) (bool, error) {
+	_, err := c.Get(path)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Download streams an uncached resource, such as a cry file, into w.
+func (c *Client) Download(url string, w io.Writer) error {
+	body, err := c.fetch(c.URL(url))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func (c *Client) fetch(url string) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(c.backoff << (attempt - 1))
+		}
+
+		body, retryAfter, err := c.do(url)
+		if err == nil {
+			return body, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		var se *StatusError
+		if errors.As(err, &se) && se.Code != http.StatusTooManyRequests && se.Code < 500 {
+			return nil, err
+		}
+		lastErr = err
+		if retryAfter > 0 {
+			time.Sleep(retryAfter)
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *Client) do(url string) ([]byte, time.Duration, error) {
+	c.wait()
+
+	resp, err := c.http.Get(url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		return body, 0, err
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, url)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, retryAfter, &StatusError{URL: url, Code: resp.StatusCode}
+	default:
+		return nil, 0, &StatusError{URL: url, Code: resp.StatusCode}
+	}
+}
+
+// wait blocks until the global rate limit allows one more request.
+func (c *Client) wait() {
+	c.mu.Lock()
+	now := time.Now()
+	start := c.next
+	if start.Before(now) {
+		start = now
+	}
+	c.next = start.Add(c.interval)
+	c.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
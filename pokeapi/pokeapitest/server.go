@@ -0,0 +1,80 @@
+// Package pokeapitest provides a fake PokeAPI that serves fixture JSON, so
+// the game and the dataset scripts can run without network access.
+package pokeapitest
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fixtures holds the bundled responses, laid out like the API:
+// "pokemon.json" answers /pokemon and "pokemon/1.json" answers /pokemon/1.
+// Files with an extension, such as "cries/1.ogg", are served as they are.
+var Fixtures fs.FS = mustSub(fixtures, "fixtures")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Handler answers GET requests from fsys and 404s anything it does not
+// have. A leading /api/v2 is accepted so both pokeapi.Client base URLs
+// (with or without the prefix) work.
+func Handler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/api/v2")
+		name = strings.Trim(name, "/")
+		if name == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if path.Ext(name) != "" {
+			http.ServeFileFS(w, r, fsys, name)
+			return
+		}
+		body, err := fs.ReadFile(fsys, name+".json")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
+
+// IDs lists, in order, the numeric IDs fsys has a response for under
+// resource: IDs(Fixtures, "pokemon") are the Pokémon the fake knows.
+func IDs(fsys fs.FS, resource string) []int {
+	entries, _ := fs.ReadDir(fsys, resource)
+	var ids []int
+	for _, e := range entries {
+		if id, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json")); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// NewServer starts a fake PokeAPI on a random local port. Point a
+// pokeapi.Client at it with Config{BaseURL: srv.URL}.
+func NewServer(fsys fs.FS) *httptest.Server {
+	return httptest.NewServer(Handler(fsys))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"pokedle/manifest"
+)
+
+// dataset returns the current Dataset. Handlers call it once per request
+// and keep the result, so a concurrent reload never mixes two datasets.
+func (s *Server) dataset() *Dataset {
+	return s.data.Load()
+}
+
+// reload validates a fresh copy of dataDir and only then swaps it in. On
+// error the current dataset stays in place.
+func (s *Server) reload() error {
+	data, err := loadDataset(s.dataDir, s.regionalTargets)
+	if err != nil {
+		return err
+	}
+	if data.source, err = s.loadSource(data.names); err != nil {
+		return err
+	}
+	if src, ok := data.source.(limitedSource); ok {
+		data.names.restrict(src.knownIDs())
+		if data.names.maxIndex() == 0 {
+			return fmt.Errorf("no daily target candidates among the %d Pokémon of the source", len(src.knownIDs()))
+		}
+		log.Printf("source limited to %d Pokémon", data.names.maxIndex())
+	}
+	if prev := s.dataset(); prev != nil {
+		data.follow(prev, time.Now())
+	}
+	s.data.Store(data)
+
+	if data.manifest == nil {
+		log.Printf("no %s in %s, dataset version unknown", manifest.FileName, s.dataDir)
+	} else {
+		log.Printf("dataset %s", data.manifest)
+	}
+	for _, problem := range data.modified {
+		log.Printf("dataset differs from its manifest: %s", problem)
+	}
+	return nil
+}
+
+// targetID returns the daily target for t. The schedule keeps the days
+// already played when a reload changes the pool, and the first answer of
+// today is pinned on top of it, so nothing a reload does can change the
+// target of a day in progress. Past days of the archive are not pinned.
+func (s *Server) targetID(t time.Time) int {
+	day := dayKey(t)
+	if day != dayKey(time.Now()) {
+		return s.schedule.target(t, s.dataset().pool())
+	}
+
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	if s.pinnedDay == day {
+		return s.pinnedID
+	}
+	s.pinnedDay = day
+	s.pinnedID = s.schedule.target(t, s.dataset().pool())
+	return s.pinnedID
+}
+
+// watchData reloads the dataset on SIGHUP and, when interval is positive,
+// whenever one of the data files changes on disk.
+func (s *Server) watchData(interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		tick = time.NewTicker(interval).C
+	}
+
+	last := dataStamp(s.dataDir)
+	for {
+		select {
+		case <-hup:
+			log.Printf("SIGHUP: reloading %s", s.dataDir)
+		case <-tick:
+			stamp := dataStamp(s.dataDir)
+			if stamp == last {
+				continue
+			}
+			last = stamp
+			log.Printf("data files changed: reloading %s", s.dataDir)
+		}
+
+		if err := s.reload(); err != nil {
+			log.Printf("reload rejected, keeping the current dataset:\n%v", err)
+			continue
+		}
+		log.Printf("dataset %s reloaded: %d names, %d daily candidates",
+			s.dataset().version(), len(s.dataset().names.rows), s.dataset().names.maxIndex())
+	}
+}
+
+// dataStamp summarises size and modification time of the data files.
+func dataStamp(dataDir string) string {
+	var b strings.Builder
+	for _, name := range []string{namesFile, genFile, evolutionFile, formsFile, catalogFile, manifest.FileName} {
+		fi, err := os.Stat(filepath.Join(dataDir, name))
+		if err != nil {
+			fmt.Fprintf(&b, "%s:missing;", name)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", name, fi.Size(), fi.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Schedule draws the daily targets. From the epoch, one day at a time, each
+// cycle walks its Pokémon in a keyed shuffle, the IDs sorted by
+// HMAC-SHA256(secret, cycle, id), so none comes back before the cycle is
+// over. A cycle takes the Pokémon of the pool on its first day: the ones
+// added later only join the next cycles, and the ones removed later keep
+// their place in the shuffle and are skipped from the day after their
+// removal. Changing the pool never changes a day already played.
+//
+// The target of a day only depends on the secret, the epoch and the pool
+// with its history; nothing is saved.
+type Schedule struct {
+	secret []byte
+	epoch  time.Time
+}
+
+// Pool is what the schedule draws from: the current targets, and when
+// Pokémon were added to or removed from them after the first build.
+type Pool struct {
+	ids     []int
+	added   map[int]time.Time
+	removed map[int]time.Time
+}
+
+func newSchedule(secret string, epoch time.Time) *Schedule {
+	return &Schedule{secret: []byte(secret), epoch: epoch}
+}
+
+// dayNumber is the number of days from the epoch to t.
+func (sc *Schedule) dayNumber(t time.Time) int {
+	day, _ := time.Parse("2006-01-02", dayKey(t))
+	return int(day.Sub(sc.epoch).Hours() / 24)
+}
+
+// number is the puzzle number of t, 1 on the epoch.
+func (sc *Schedule) number(t time.Time) int {
+	return sc.dayNumber(t) + 1
+}
+
+// target returns the target of t.
+func (sc *Schedule) target(t time.Time, p Pool) int {
+	n := sc.dayNumber(t)
+	if n < 0 || len(p.ids) == 0 {
+		return 0
+	}
+	order, i := sc.walk(n, p)
+	return order[i]
+}
+
+// remaining is the number of Pokémon left in the cycle of t once its target
+// is drawn.
+func (sc *Schedule) remaining(t time.Time, p Pool) int {
+	n := sc.dayNumber(t)
+	if n < 0 || len(p.ids) == 0 {
+		return 0
+	}
+	order, i := sc.walk(n, p)
+	left := 0
+	for _, id := range order[i+1:] {
+		if !sc.gone(p, id, n+1) {
+			left++
+		}
+	}
+	return left
+}
+
+// walk returns the order of the cycle that day number n falls in, and the
+// index of the target of n in it. A cycle never starts with the last
+// target of the previous one.
+func (sc *Schedule) walk(n int, p Pool) ([]int, int) {
+	day, prev := 0, 0
+	for c := 0; ; c++ {
+		order := sc.shuffle(sc.members(day, p), c)
+		if len(order) > 1 && order[0] == prev {
+			order[0], order[1] = order[1], order[0]
+		}
+		for i, id := range order {
+			if sc.gone(p, id, day) {
+				continue
+			}
+			if day == n {
+				return order, i
+			}
+			day++
+			prev = id
+		}
+	}
+}
+
+// members returns the Pokémon of the cycle starting on day number start:
+// the pool, with the ones removed since, but without the ones added on that
+// day or later. A pool added entirely after the epoch plays whole from the
+// first cycle.
+func (sc *Schedule) members(start int, p Pool) []int {
+	ids := slices.Clone(p.ids)
+	for id := range p.removed {
+		if !slices.Contains(p.ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	ids = slices.DeleteFunc(ids, func(id int) bool {
+		at, ok := p.added[id]
+		return ok && sc.dayNumber(at) >= start || sc.gone(p, id, start)
+	})
+	if len(ids) == 0 {
+		return slices.Clone(p.ids)
+	}
+	return ids
+}
+
+// gone reports whether id left the pool before day number n. A Pokémon
+// removed during a day is still the target of that day.
+func (sc *Schedule) gone(p Pool, id, n int) bool {
+	at, ok := p.removed[id]
+	return ok && sc.dayNumber(at) < n && !slices.Contains(p.ids, id)
+}
+
+// shuffle orders ids for cycle by the keyed hash of each ID.
+func (sc *Schedule) shuffle(ids []int, cycle int) []int {
+	keys := make(map[int]uint64, len(ids))
+	for _, id := range ids {
+		m := hmac.New(sha256.New, sc.secret)
+		m.Write([]byte(strconv.Itoa(cycle) + ":" + strconv.Itoa(id)))
+		keys[id] = binary.BigEndian.Uint64(m.Sum(nil))
+	}
+	slices.SortFunc(ids, func(a, b int) int {
+		switch {
+		case keys[a] < keys[b]:
+			return -1
+		case keys[a] > keys[b]:
+			return 1
+		}
+		return a - b
+	})
+	return ids
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScheduleTarget(t *testing.T) {
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sc := newSchedule("test", epoch)
+	day := func(n int) time.Time { return epoch.AddDate(0, 0, n) }
+	targets := func(p Pool) []int {
+		var ids []int
+		for n := range 60 {
+			ids = append(ids, sc.target(day(n), p))
+		}
+		return ids
+	}
+
+	base := Pool{ids: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+	planned := targets(base)
+	// played was drawn on day 12, before its removal on day 15; late was
+	// still to come on day 17.
+	played, late := planned[12], planned[17]
+	without := func(id int) []int {
+		return slices.DeleteFunc(slices.Clone(base.ids), func(x int) bool { return x == id })
+	}
+
+	tests := []struct {
+		name string
+		pool Pool
+		// same is the number of days from the epoch that keep the targets
+		// of base.
+		same int
+		// cycles are the day numbers the cycles start on.
+		cycles []int
+		// gone is an ID no longer drawn from the day after its removal.
+		gone int
+	}{
+		{
+			name:   "fixed pool",
+			pool:   base,
+			same:   60,
+			cycles: []int{0, 10, 20, 30, 40, 50},
+		},
+		{
+			name: "added mid-cycle",
+			pool: Pool{
+				ids:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+				added: map[int]time.Time{11: day(15), 12: day(15)},
+			},
+			same:   20,
+			cycles: []int{0, 10, 20, 32, 44, 56},
+		},
+		{
+			name: "removed before its day",
+			pool: Pool{
+				ids:     without(late),
+				removed: map[int]time.Time{late: day(15)},
+			},
+			same:   17,
+			cycles: []int{0, 10, 19, 28, 37, 46, 55},
+			gone:   late,
+		},
+		{
+			name: "removed after its day",
+			pool: Pool{
+				ids:     without(played),
+				removed: map[int]time.Time{played: day(15)},
+			},
+			same:   20,
+			cycles: []int{0, 10, 20, 29, 38, 47, 56},
+			gone:   played,
+		},
+		{
+			name: "removed during its day",
+			pool: Pool{
+				ids:     without(late),
+				removed: map[int]time.Time{late: day(17).Add(12 * time.Hour)},
+			},
+			same:   20,
+			cycles: []int{0, 10, 20, 29, 38, 47, 56},
+			gone:   late,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := targets(tt.pool)
+			if !slices.Equal(got[:tt.same], planned[:tt.same]) {
+				t.Errorf("first %d days %v, want %v", tt.same, got[:tt.same], planned[:tt.same])
+			}
+			for i, start := range tt.cycles {
+				end := len(got)
+				if i+1 < len(tt.cycles) {
+					end = tt.cycles[i+1]
+				}
+				cycle := got[start:end]
+				if len(slices.Compact(slices.Sorted(slices.Values(cycle)))) != len(cycle) {
+					t.Errorf("cycle from day %d repeats: %v", start, cycle)
+				}
+				if start > 0 && got[start] == got[start-1] {
+					t.Errorf("cycle from day %d starts with the last target %d", start, got[start])
+				}
+			}
+			if tt.gone != 0 {
+				from := sc.dayNumber(tt.pool.removed[tt.gone]) + 1
+				if slices.Contains(got[from:], tt.gone) {
+					t.Errorf("%d drawn after its removal: %v", tt.gone, got[from:])
+				}
+			}
+		})
+	}
+}
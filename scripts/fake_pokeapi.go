@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"pokedle/pokeapi/pokeapitest"
+)
+
+// Serves the bundled PokeAPI fixtures so pokedle-data can run offline:
+//
+//	POKEAPI_BASE_URL=http://localhost:8081 POKEAPI_CACHE_DIR=off go run ./cmd/pokedle-data build names
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8081"
+	}
+	fmt.Printf("Fake PokeAPI running on http://localhost:%s\n", port)
+	log.Fatal(http.ListenAndServe(":"+port, pokeapitest.Handler(pokeapitest.Fixtures)))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newFakeServer starts a server on the bundled fixtures, with its state in
+// memory only.
+func newFakeServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("POKEDLE_SOURCE", "fake")
+	t.Setenv("POKEDLE_STATE_DIR", "off")
+	t.Setenv("POKEDLE_MAX_GUESSES", "")
+	t.Setenv("POKEAPI_CACHE_DIR", "off")
+	s, err := NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// player replays the cookies the server sets, as a browser would.
+type player struct {
+	t       *testing.T
+	cookies map[string]*http.Cookie
+}
+
+func (p *player) do(h http.HandlerFunc, method, url string, body any, v any) {
+	p.t.Helper()
+	var req *http.Request
+	if body != nil {
+		b, _ := json.Marshal(body)
+		req = httptest.NewRequest(method, url, strings.NewReader(string(b)))
+	} else {
+		req = httptest.NewRequest(method, url, nil)
+	}
+	for _, c := range p.cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	if rec.Code != http.StatusOK {
+		p.t.Fatalf("%s %s: status %d: %s", method, url, rec.Code, rec.Body)
+	}
+	if p.cookies == nil {
+		p.cookies = make(map[string]*http.Cookie)
+	}
+	for _, c := range rec.Result().Cookies() {
+		p.cookies[c.Name] = c
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		p.t.Fatalf("%s %s: %v", method, url, err)
+	}
+}
+
+func (p *player) guess(s *Server, name string) GuessResp {
+	p.t.Helper()
+	var resp GuessResp
+	p.do(s.handleGuess, http.MethodPost, "/api/guess", GuessReq{Guess: name, Lang: "en"}, &resp)
+	return resp
+}
+
+// misses returns the English names of the fixtures other than the target.
+func misses(s *Server, target int) []string {
+	names := s.dataset().names
+	var out []string
+	for _, id := range names.targets {
+		if id != target {
+			out = append(out, names.enById[id])
+		}
+	}
+	return out
+}
+
+func TestFakePoolIsFixtures(t *testing.T) {
+	s := newFakeServer(t)
+	names := s.dataset().names
+	for _, id := range names.targets {
+		if _, err := s.dataset().source.Pokemon(id); err != nil {
+			t.Errorf("target %d: %v", id, err)
+		}
+	}
+	if _, ok := names.lookup(normalizeKey("Pikachu")); ok {
+		t.Error("Pikachu is a name in fake mode but has no fixture")
+	}
+}
+
+func TestGuess(t *testing.T) {
+	s := newFakeServer(t)
+	target := s.targetID(time.Now().UTC())
+	p := &player{t: t}
+
+	wrong := misses(s, target)[0]
+	resp := p.guess(s, wrong)
+	if !resp.OK || resp.Correct || resp.GuessCounter != 1 {
+		t.Fatalf("guess %s: %+v", wrong, resp)
+	}
+	for _, k := range []string{"type1Match", "weightHint", "heightHint", "distance"} {
+		if _, ok := resp.Hints[k]; !ok {
+			t.Errorf("guess %s: no %s hint", wrong, k)
+		}
+	}
+
+	if resp = p.guess(s, wrong); !resp.Duplicate || resp.GuessCounter != 1 {
+		t.Fatalf("guess %s again: %+v", wrong, resp)
+	}
+
+	resp = p.guess(s, s.dataset().names.enById[target])
+	if !resp.Correct || resp.Outcome != "won" || resp.GuessCounter != 2 {
+		t.Fatalf("guess target: %+v", resp)
+	}
+	if resp.Reveal["id"] != float64(target) {
+		t.Errorf("reveal: %v, want id %d", resp.Reveal, target)
+	}
+}
+
+func TestHints(t *testing.T) {
+	s := newFakeServer(t)
+	target := s.targetID(time.Now().UTC())
+	p := &player{t: t}
+
+	cry := fmt.Sprintf("static/hint_%d.ogg", target)
+	if _, err := os.Stat(cry); os.IsNotExist(err) {
+		t.Cleanup(func() { os.Remove(cry) })
+	}
+
+	var hints map[string]any
+	p.do(s.handleHints, http.MethodGet, "/api/hints", nil, &hints)
+	if hints["tier"] != float64(0) || hints["cry"] != nil {
+		t.Fatalf("hints before guessing: %v", hints)
+	}
+
+	for _, name := range misses(s, target)[:3] {
+		p.guess(s, name)
+	}
+	p.do(s.handleHints, http.MethodGet, "/api/hints", nil, &hints)
+	if hints["tier"] != float64(1) || hints["cry"] != "/"+cry {
+		t.Fatalf("hints after 3 misses: %v", hints)
+	}
+	if hints["types"] != nil {
+		t.Errorf("types at tier 1: %v", hints["types"])
+	}
+}
+
+func TestGuessAfterRestore(t *testing.T) {
+	s := newFakeServer(t)
+	s.sessions.secret = []byte("test")
+	target := s.targetID(time.Now().UTC())
+	p := &player{t: t}
+
+	wrong := misses(s, target)
+	p.guess(s, wrong[0])
+	p.guess(s, wrong[1])
+
+	// A new server has lost the session and restores it from the cookie.
+	s = newFakeServer(t)
+	s.sessions.secret = []byte("test")
+	if resp := p.guess(s, wrong[2]); !resp.OK || resp.GuessCounter != 3 {
+		t.Fatalf("guess after restore: %+v", resp)
+	}
+
+	var history struct {
+		Guesses []GuessEntry `json:"guesses"`
+	}
+	p.do(s.handleHistory, http.MethodGet, "/api/history", nil, &history)
+	names := s.dataset().names
+	var got []string
+	for _, g := range history.Guesses {
+		got = append(got, names.enById[g.ID])
+	}
+	if strings.Join(got, ",") != strings.Join(wrong[:3], ",") {
+		t.Errorf("history %v, want %v", got, wrong[:3])
+	}
+}
+
+func TestGuessCapUnderConcurrency(t *testing.T) {
+	s := newFakeServer(t)
+	s.maxGuesses = 2
+	target := s.targetID(time.Now().UTC())
+	p := &player{t: t}
+
+	wrong := misses(s, target)
+	p.guess(s, wrong[0])
+
+	// The last two misses race for the one guess left.
+	resps := make([]GuessResp, 2)
+	var wg sync.WaitGroup
+	for i, name := range wrong[1:3] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, _ := json.Marshal(GuessReq{Guess: name, Lang: "en"})
+			req := httptest.NewRequest(http.MethodPost, "/api/guess", strings.NewReader(string(b)))
+			for _, c := range p.cookies {
+				req.AddCookie(c)
+			}
+			rec := httptest.NewRecorder()
+			s.handleGuess(rec, req)
+			json.Unmarshal(rec.Body.Bytes(), &resps[i])
+		}()
+	}
+	wg.Wait()
+
+	ok := 0
+	for _, resp := range resps {
+		if resp.OK {
+			ok++
+		} else if resp.Outcome != "lost" {
+			t.Errorf("guess past the cap: %+v", resp)
+		}
+		if resp.GuessCounter != 2 {
+			t.Errorf("guess counter %d, want 2", resp.GuessCounter)
+		}
+	}
+	if ok != 1 {
+		t.Errorf("%d guesses counted past the first, want 1", ok)
+	}
+}
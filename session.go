@@ -0,0 +1,387 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	sessionCookie = "session"
+	stateCookie   = "state"
+	// sessionsDir holds one JSON file per session in the state directory.
+	sessionsDir = "sessions"
+	// sessionMaxIdle drops sessions nobody used for that long.
+	sessionMaxIdle = 90 * 24 * time.Hour
+	// sessionFlushInterval is how often the changed sessions are written.
+	sessionFlushInterval = 2 * time.Second
+	// devDayLength replaces the day in dev mode, so a game can be replayed.
+	devDayLength = 5 * time.Minute
+)
+
+// DayState is one player's game for one day.
+type DayState struct {
+	Guesses []int `json:"guesses"`
+	// History holds the board of each guess, in order. A state restored
+	// from the state cookie only has Guesses until handleHistory fills it.
+	History []GuessEntry `json:"history"`
+	Solved  bool         `json:"solved"`
+	// Lost is set when the last allowed guess missed; the day is over.
+	Lost bool `json:"lost,omitempty"`
+	// Counted is set once the finished game is in the player's stats.
+	Counted  bool      `json:"counted,omitempty"`
+	HintTier int       `json:"hintTier"`
+	Updated  time.Time `json:"updated"`
+}
+
+// GuessEntry is one guess as the board shows it.
+type GuessEntry struct {
+	ID      int            `json:"id"`
+	Correct bool           `json:"correct"`
+	Guess   map[string]any `json:"guess"`
+	Hints   map[string]any `json:"hints"`
+}
+
+// over reports whether the day's game has ended, won or lost.
+func (ds DayState) over() bool {
+	return ds.Solved || ds.Lost
+}
+
+// Session is a player, identified by the random ID of its cookie.
+type Session struct {
+	Days  map[string]*DayState `json:"days"`
+	Stats Stats                `json:"stats"`
+	// Archive are the stats of the past days played from the archive.
+	Archive Stats     `json:"archive"`
+	Seen    time.Time `json:"seen"`
+}
+
+// SessionStore keeps the game state on the server, so clearing or editing
+// cookies can neither reset a game nor unlock hints. A session only starts
+// with the player's first guess. With a directory, persist writes the
+// sessions changed since its last pass, one file each.
+//
+// With a secret, every change also sets a state cookie signed with it, and
+// a session the store has lost (memory only, a new server) is restored
+// from that cookie.
+type SessionStore struct {
+	mu       sync.Mutex
+	dir      string
+	secret   []byte
+	sessions map[string]*Session
+	// dirty are the IDs of the sessions changed since the last flush.
+	dirty map[string]bool
+}
+
+// signedState is the payload of the state cookie.
+type signedState struct {
+	Day     string `json:"day"`
+	Guesses []int  `json:"guesses"`
+	Solved  bool   `json:"solved"`
+	Lost    bool   `json:"lost,omitempty"`
+}
+
+// hintTier is the hint tier unlocked after a number of guesses.
+func hintTier(guesses int) int {
+	return guesses / 3
+}
+
+// newSessionStore loads the sessions saved in stateDir. An empty stateDir
+// keeps them in memory only, an empty secret disables the state cookie.
+func newSessionStore(stateDir, secret string) (*SessionStore, error) {
+	st := &SessionStore{sessions: make(map[string]*Session), dirty: make(map[string]bool)}
+	if secret != "" {
+		st.secret = []byte(secret)
+	}
+	if stateDir == "" {
+		return st, nil
+	}
+	st.dir = filepath.Join(stateDir, sessionsDir)
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(st.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var sess Session
+		if err := json.Unmarshal(body, &sess); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if sess.Days == nil {
+			sess.Days = make(map[string]*DayState)
+		}
+		st.sessions[strings.TrimSuffix(filepath.Base(file), ".json")] = &sess
+	}
+	return st, nil
+}
+
+// sessionID returns the ID of the player's session, or "" when the request
+// has no session cookie the store knows. It never starts a session.
+func (st *SessionStore) sessionID(r *http.Request) string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.known(r)
+}
+
+// known is sessionID for a caller holding st.mu. Seen is only saved again
+// once a day, so reading the game does not rewrite the session.
+func (st *SessionStore) known(r *http.Request) string {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+	sess, ok := st.sessions[c.Value]
+	if !ok {
+		return ""
+	}
+	if time.Since(sess.Seen) > 24*time.Hour {
+		st.dirty[c.Value] = true
+	}
+	sess.Seen = time.Now()
+	return c.Value
+}
+
+// open returns the player's session ID and a copy of its state for day.
+// A valid state cookie further along than the stored state replaces it.
+// Without a session the ID is "" and the state is the one of the cookie.
+func (st *SessionStore) open(r *http.Request, day string) (string, DayState) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	id := st.known(r)
+	if id == "" {
+		var ds DayState
+		st.restore(r, day, &ds)
+		return "", ds.copy()
+	}
+	ds := st.state(id, day)
+	if st.restore(r, day, ds) {
+		st.dirty[id] = true
+	}
+	return id, ds.copy()
+}
+
+// start starts a session for the player's first guess of day and sets its
+// cookie. The day starts from the state cookie, when it has one.
+func (st *SessionStore) start(w http.ResponseWriter, r *http.Request, day string) string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	id := hex.EncodeToString(buf)
+	st.sessions[id] = &Session{Days: make(map[string]*DayState), Seen: time.Now()}
+	st.restore(r, day, st.state(id, day))
+	st.dirty[id] = true
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		Expires:  time.Now().AddDate(1, 0, 0),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+// restore replaces ds by the state cookie of r when the cookie is valid
+// and further along, and reports whether it did. The caller holds st.mu.
+func (st *SessionStore) restore(r *http.Request, day string, ds *DayState) bool {
+	// Dev mode restarts the day, which the cookie must not undo.
+	signed, ok := st.verify(r, day)
+	if !ok || isDevMode || len(signed.Guesses) <= len(ds.Guesses) {
+		return false
+	}
+	ds.Guesses = signed.Guesses
+	ds.Solved = signed.Solved
+	ds.Lost = signed.Lost
+	ds.HintTier = hintTier(len(ds.Guesses))
+	ds.Updated = time.Now()
+	return true
+}
+
+// update changes the player's state for day in session id, refreshes the
+// state cookie and returns the new state. The session is saved on the next
+// flush.
+func (st *SessionStore) update(w http.ResponseWriter, id, day string, change func(*DayState)) DayState {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	ds := st.state(id, day)
+	change(ds)
+	ds.Updated = time.Now()
+	st.dirty[id] = true
+	st.sign(w, day, ds)
+	return ds.copy()
+}
+
+func (st *SessionStore) state(id, day string) *DayState {
+	sess, ok := st.sessions[id]
+	if !ok {
+		sess = &Session{Days: make(map[string]*DayState), Seen: time.Now()}
+		st.sessions[id] = sess
+	}
+	ds, ok := sess.Days[day]
+	if !ok || (isDevMode && time.Since(ds.Updated) > devDayLength) {
+		ds = &DayState{}
+		sess.Days[day] = ds
+	}
+	return ds
+}
+
+// sign sets the state cookie for day: the JSON payload and its HMAC-SHA256,
+// both base64url encoded and joined by a dot.
+func (st *SessionStore) sign(w http.ResponseWriter, day string, ds *DayState) {
+	// The cookie only holds today's game; archive games live on the server.
+	if st.secret == nil || isArchiveKey(day) {
+		return
+	}
+	payload, err := json.Marshal(signedState{Day: day, Guesses: ds.Guesses, Solved: ds.Solved, Lost: ds.Lost})
+	if err != nil {
+		log.Printf("signing state: %v", err)
+		return
+	}
+	value := base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(st.mac(payload))
+
+	t, _ := time.Parse("2006-01-02", day)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    value,
+		Path:     "/",
+		Expires:  t.AddDate(0, 0, 1),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// verify returns the state cookie of r when its signature is valid and it
+// is for day. A cookie from another day is rejected whatever its Expires.
+func (st *SessionStore) verify(r *http.Request, day string) (signedState, bool) {
+	var signed signedState
+	if st.secret == nil || isArchiveKey(day) {
+		return signed, false
+	}
+	c, err := r.Cookie(stateCookie)
+	if err != nil {
+		return signed, false
+	}
+	enc, encMAC, ok := strings.Cut(c.Value, ".")
+	if !ok {
+		return signed, false
+	}
+	payload, err1 := base64.RawURLEncoding.DecodeString(enc)
+	mac, err2 := base64.RawURLEncoding.DecodeString(encMAC)
+	if err1 != nil || err2 != nil || !hmac.Equal(mac, st.mac(payload)) {
+		return signed, false
+	}
+	if err := json.Unmarshal(payload, &signed); err != nil || signed.Day != day {
+		return signed, false
+	}
+	return signed, true
+}
+
+func (st *SessionStore) mac(payload []byte) []byte {
+	m := hmac.New(sha256.New, st.secret)
+	m.Write([]byte(stateCookie + ":"))
+	m.Write(payload)
+	return m.Sum(nil)
+}
+
+func (ds *DayState) copy() DayState {
+	c := *ds
+	c.Guesses = append([]int(nil), ds.Guesses...)
+	c.History = append([]GuessEntry(nil), ds.History...)
+	return c
+}
+
+// persist flushes the store every interval, and once more before the
+// process exits on SIGINT or SIGTERM. It returns at once for a store kept
+// in memory only.
+func (st *SessionStore) persist(interval time.Duration) {
+	if st.dir == "" {
+		return
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	tick := time.NewTicker(interval)
+	for {
+		select {
+		case <-tick.C:
+			st.flush()
+		case sig := <-stop:
+			st.flush()
+			log.Printf("%v: sessions saved, exiting", sig)
+			os.Exit(0)
+		}
+	}
+}
+
+// flush writes the sessions changed since the last flush, one file each,
+// and removes the idle ones. st.mu is only held to encode them.
+func (st *SessionStore) flush() {
+	st.mu.Lock()
+	for id, sess := range st.sessions {
+		if time.Since(sess.Seen) > sessionMaxIdle {
+			delete(st.sessions, id)
+			st.dirty[id] = true
+		}
+	}
+	bodies := make(map[string][]byte, len(st.dirty))
+	for id := range st.dirty {
+		sess, ok := st.sessions[id]
+		if !ok {
+			bodies[id] = nil
+			continue
+		}
+		body, err := json.Marshal(sess)
+		if err != nil {
+			log.Printf("saving session %s: %v", id, err)
+			continue
+		}
+		bodies[id] = body
+	}
+	clear(st.dirty)
+	st.mu.Unlock()
+
+	for id, body := range bodies {
+		path := filepath.Join(st.dir, id+".json")
+		var err error
+		if body == nil {
+			err = os.Remove(path)
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		} else {
+			tmp := path + ".tmp"
+			if err = os.WriteFile(tmp, body, 0o644); err == nil {
+				err = os.Rename(tmp, path)
+			}
+		}
+		if err != nil {
+			log.Printf("saving session %s: %v", id, err)
+		}
+	}
+}
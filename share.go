@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Cells of the share grid.
+const (
+	cellMatch      = "🟩"
+	cellWrongPlace = "🟨"
+	cellMiss       = "🟥"
+	cellHigher     = "⬆️"
+	cellLower      = "⬇️"
+)
+
+// shareRow turns the hints of one guess into a row of cells: type 1,
+// type 2, generation, evolution position, fully evolved, height and weight.
+// It names no Pokémon, so the grid spoils nothing.
+func shareRow(hints map[string]any) string {
+	var b strings.Builder
+	for _, t := range []string{"type1", "type2"} {
+		switch {
+		case hints[t+"Match"] == true:
+			b.WriteString(cellMatch)
+		case hints[t+"MatchWrongPlace"] == true:
+			b.WriteString(cellWrongPlace)
+		default:
+			b.WriteString(cellMiss)
+		}
+	}
+	b.WriteString(compareCell(hints["guessedGen"], hints["correctGen"]))
+	b.WriteString(compareCell(hints["guessPosition"], hints["targetPosition"]))
+	if hintNumber(hints["guessFullyEvolved"]) == hintNumber(hints["targetFullyEvolved"]) {
+		b.WriteString(cellMatch)
+	} else {
+		b.WriteString(cellMiss)
+	}
+	for _, h := range []string{"heightHint", "weightHint"} {
+		hint, _ := hints[h].(string)
+		switch {
+		case strings.HasPrefix(hint, ">"):
+			b.WriteString(cellHigher)
+		case strings.HasPrefix(hint, "<"):
+			b.WriteString(cellLower)
+		default:
+			b.WriteString(cellMatch)
+		}
+	}
+	return b.String()
+}
+
+// compareCell tells whether the target value is the guessed one, higher or
+// lower.
+func compareCell(guess, target any) string {
+	g, t := hintNumber(guess), hintNumber(target)
+	switch {
+	case g == t:
+		return cellMatch
+	case g < t:
+		return cellHigher
+	default:
+		return cellLower
+	}
+}
+
+// hintNumber reads a numeric hint, an int when it was just computed or a
+// float64 when it comes back from the saved sessions.
+func hintNumber(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// handleShare returns the puzzle's result as a Wordle-style emoji grid, once
+// the game is over.
+func (s *Server) handleShare(w http.ResponseWriter, r *http.Request) {
+	p, err := s.puzzle(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sid, state := s.sessions.open(r, p.key())
+	if !state.over() {
+		writeJSON(w, map[string]any{"ok": false, "error": "Finish the game first"})
+		return
+	}
+	state, err = s.fillHistory(w, sid, p, state)
+	if err != nil {
+		writeJSON(w, map[string]any{"ok": false, "error": "PokeAPI Error"})
+		return
+	}
+
+	number := s.schedule.number(p.t)
+	score := strconv.Itoa(len(state.Guesses))
+	if state.Lost {
+		score = "X"
+	}
+	if s.maxGuesses > 0 {
+		score += "/" + strconv.Itoa(s.maxGuesses)
+	}
+
+	lines := []string{fmt.Sprintf("Pokédle #%d %s", number, score), ""}
+	for _, entry := range state.History {
+		lines = append(lines, shareRow(entry.Hints))
+	}
+	writeJSON(w, map[string]any{
+		"ok":     true,
+		"puzzle": number,
+		"text":   strings.Join(lines, "\n"),
+	})
+}
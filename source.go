@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pokedle/pokeapi"
+	"pokedle/pokeapi/pokeapitest"
+)
+
+// PokemonSource is the seam between the game and wherever the per-Pokémon
+// data comes from: the offline catalog, PokeAPI, or a fake PokeAPI.
+type PokemonSource interface {
+	Pokemon(id int) (*Pokemon, error)
+	// Cry returns the remote URL of the latest cry.
+	Cry(id int) (string, error)
+	// Types returns the upper-cased type names in slot order.
+	Types(id int) ([]string, error)
+	// Descriptions returns one flavor text per language; handleHints keeps
+	// the languages of the dataset.
+	Descriptions(id int) (map[string]string, error)
+}
+
+var errUnknownPokemon = errors.New("unknown pokemon")
+
+// A sourceLoader builds the PokemonSource of a freshly loaded name index.
+// The server calls it on every reload, so a rebuilt catalog is swapped in
+// together with the CSV files it was built from.
+type sourceLoader func(names *NameIndex) (PokemonSource, error)
+
+// newSource picks the PokemonSource from POKEDLE_SOURCE:
+//   - "catalog" (default): the offline catalog, with PokeAPI as a fallback
+//     only when POKEDLE_LIVE_FALLBACK=1. Without the fallback a missing
+//     catalog, or one lacking a Pokémon of the names file, is an error, so
+//     the server never calls PokeAPI by surprise.
+//   - "live": PokeAPI only
+//   - "fake": an in-process fake PokeAPI serving the bundled fixtures
+func newSource(api *pokeapi.Client, catalogPath string) (sourceLoader, error) {
+	switch mode := os.Getenv("POKEDLE_SOURCE"); mode {
+	case "live":
+		src := liveSource{api: api}
+		return func(*NameIndex) (PokemonSource, error) { return src, nil }, nil
+	case "fake":
+		srv := pokeapitest.NewServer(pokeapitest.Fixtures)
+		log.Printf("serving PokeAPI fixtures on %s", srv.URL)
+		src := fakeSource{
+			liveSource: liveSource{api: pokeapi.NewClient(pokeapi.Config{BaseURL: srv.URL})},
+			url:        srv.URL,
+			ids:        pokeapitest.IDs(pokeapitest.Fixtures, "pokemon"),
+		}
+		return func(*NameIndex) (PokemonSource, error) { return src, nil }, nil
+	case "", "catalog":
+		fallback := os.Getenv("POKEDLE_LIVE_FALLBACK") == "1"
+		return func(names *NameIndex) (PokemonSource, error) {
+			catalog, err := loadCatalog(catalogPath)
+			if err != nil {
+				if !fallback {
+					return nil, fmt.Errorf("loading catalog: %w (build it with `go run ./cmd/pokedle-data build catalog`, or set POKEDLE_LIVE_FALLBACK=1)", err)
+				}
+				log.Printf("catalog unavailable (%v), falling back to live PokeAPI", err)
+				return liveSource{api: api}, nil
+			}
+			log.Printf("catalog loaded: %d Pokémon", catalog.size())
+			if fallback {
+				return fallbackSource{catalog, liveSource{api: api}}, nil
+			}
+			if missing := catalog.missing(names); len(missing) > 0 {
+				return nil, fmt.Errorf("%s: no entry for %d Pokémon of %s, such as %d (rebuild it with `go run ./cmd/pokedle-data build catalog`)",
+					filepath.Base(catalogPath), len(missing), namesFile, missing[0])
+			}
+			return catalog, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown POKEDLE_SOURCE %q", mode)
+	}
+}
+
+// A limitedSource only knows some Pokémon. The server narrows the names
+// and the daily targets to them, so every guess can be answered.
+type limitedSource interface {
+	knownIDs() []int
+}
+
+// fakeSource is PokeAPI played from the bundled fixtures, cries included,
+// so a whole game runs offline.
+type fakeSource struct {
+	liveSource
+	url string
+	ids []int
+}
+
+func (f fakeSource) knownIDs() []int { return f.ids }
+
+func (f fakeSource) Cry(id int) (string, error) {
+	if _, err := f.liveSource.Cry(id); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/cries/%d.ogg", f.url, id), nil
+}
+
+// fallbackSource asks each source in turn until one knows the Pokémon.
+type fallbackSource []PokemonSource
+
+func (f fallbackSource) Pokemon(id int) (*Pokemon, error) {
+	return firstOf(f, func(src PokemonSource) (*Pokemon, error) { return src.Pokemon(id) })
+}
+
+func (f fallbackSource) Cry(id int) (string, error) {
+	return firstOf(f, func(src PokemonSource) (string, error) { return src.Cry(id) })
+}
+
+func (f fallbackSource) Types(id int) ([]string, error) {
+	return firstOf(f, func(src PokemonSource) ([]string, error) { return src.Types(id) })
+}
+
+func (f fallbackSource) Descriptions(id int) (map[string]string, error) {
+	return firstOf(f, func(src PokemonSource) (map[string]string, error) { return src.Descriptions(id) })
+}
+
+func firstOf[T any](sources []PokemonSource, get func(PokemonSource) (T, error)) (T, error) {
+	var zero T
+	err := errUnknownPokemon
+	for _, src := range sources {
+		v, e := get(src)
+		if e == nil {
+			return v, nil
+		}
+		err = e
+	}
+	return zero, err
+}
+
+// liveSource reads everything from PokeAPI through the shared client.
+type liveSource struct {
+	api *pokeapi.Client
+}
+
+func (l liveSource) Pokemon(id int) (*Pokemon, error) {
+	var p Pokemon
+	if err := l.api.GetJSON(fmt.Sprintf("pokemon/%d", id), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (l liveSource) detail(id int) (*PokemonDetail, error) {
+	var p PokemonDetail
+	if err := l.api.GetJSON(fmt.Sprintf("pokemon/%d", id), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (l liveSource) Cry(id int) (string, error) {
+	p, err := l.detail(id)
+	if err != nil {
+		return "", err
+	}
+	return p.Cries.Latest, nil
+}
+
+func (l liveSource) Types(id int) ([]string, error) {
+	p, err := l.detail(id)
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for _, t := range p.Types {
+		types = append(types, strings.ToUpper(t.Type.Name))
+	}
+	return types, nil
+}
+
+func (l liveSource) Descriptions(id int) (map[string]string, error) {
+	var data SpeciesResponse
+	if err := l.api.GetJSON(fmt.Sprintf("pokemon-species/%d", id), &data); err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]string)
+	for _, entry := range data.FlavorTextEntries {
+		lang := entry.Language.Name
+		if _, ok := descriptions[lang]; !ok {
+			descriptions[lang] = cleanFlavorText(entry.FlavorText)
+		}
+	}
+	return descriptions, nil
+}
@@ -8,6 +8,37 @@ document.addEventListener("DOMContentLoaded", function() {
   const statusHints = document.getElementById("hints-status");
   const hintsDynamic = document.getElementById("hints-dynamic");
   const hintsBox = document.getElementById("hints");
+  const statsBox = document.getElementById("stats");
+  const archiveLink = document.getElementById("archiveLink");
+  const archiveList = document.getElementById("archive");
+  const langsEl = document.getElementById("langs");
+
+  // langs are the name languages of the dataset, filled from /api/dataset.
+  let langs = [];
+
+  // nameLang maps the browser locales onto a name language of the dataset.
+  // Chinese picks its script from the region when the locale names none,
+  // and Japanese prefers the kanji names over the kana ones (ja-Hrkt).
+  function nameLang() {
+    for (const locale of navigator.languages || [navigator.language]) {
+      const tag = locale.toLowerCase();
+      let candidates = [tag, tag.split("-")[0]];
+      if (tag.startsWith("zh")) {
+        candidates = /-(hant|tw|hk|mo)\b/.test(tag) ? ["zh-hant"] : ["zh-hans"];
+      } else if (tag.startsWith("ja")) {
+        candidates = /-(hrkt|kana)\b/.test(tag) ? ["ja-hrkt", "ja"] : ["ja", "ja-hrkt"];
+      }
+      for (const c of candidates) {
+        const lang = langs.find(l => l.toLowerCase() === c);
+        if (lang) return lang;
+      }
+    }
+    return "en";
+  }
+
+  // ?date= plays a past day from the archive.
+  const archiveDate = new URLSearchParams(location.search).get("date");
+  const dateQuery = archiveDate ? `?date=${encodeURIComponent(archiveDate)}` : "";
 
   const suggestBox = document.createElement("ul");
   suggestBox.id = "suggestions";
@@ -39,10 +70,10 @@ document.addEventListener("DOMContentLoaded", function() {
     }
 
     try {
-      const res = await fetch("/api/suggest", {
+      const res = await fetch(`/api/suggest${dateQuery}`, {
         method: "POST",
         headers: { "Content-Type": "application/json" },
-        body: JSON.stringify({ query: q }),
+        body: JSON.stringify({ query: q, lang: nameLang(), limit: 20, guessed: "exclude" }),
       });
 
       const suggestionsGroups = await res.json();
@@ -165,24 +196,25 @@ document.addEventListener("DOMContentLoaded", function() {
       return;
     }
     hintsBox.style.display = "block";
+    const attempt = data.maxGuesses ? `${data.guessCounter}/${data.maxGuesses}` : `${data.guessCounter}`;
     const tier = Math.floor(data.guessCounter / 3);
     switch (tier) {
       case 0:
-        statusHints.textContent = `Attempt #${data.guessCounter}. ${3 - data.guessCounter} more guess(es) before Hint #1.`;
+        statusHints.textContent = `Attempt #${attempt}. ${3 - data.guessCounter} more guess(es) before Hint #1.`;
         break;
       case 1:
-        statusHints.textContent = `Attempt #${data.guessCounter}. ${6 - data.guessCounter} more guess(es) before Hint #2.`;
+        statusHints.textContent = `Attempt #${attempt}. ${6 - data.guessCounter} more guess(es) before Hint #2.`;
         break;
       case 2:
-        statusHints.textContent = `Attempt #${data.guessCounter}. ${9 - data.guessCounter} more guess(es) before Hint #3.`;
+        statusHints.textContent = `Attempt #${attempt}. ${9 - data.guessCounter} more guess(es) before Hint #3.`;
         break;
       default:
-        statusHints.textContent = `Attempt #${data.guessCounter}`;
+        statusHints.textContent = `Attempt #${attempt}`;
     }
   }
 
   async function updateHints() {
-    const res = await fetch("/api/hints");
+    const res = await fetch(`/api/hints${dateQuery}`);
     const data = await res.json();
 
     hintsDynamic.innerHTML = "";
@@ -333,6 +365,198 @@ document.addEventListener("DOMContentLoaded", function() {
     }
   }
 
+  function addGuess(entry) {
+    const li = document.createElement("li");
+    li.className = "guess";
+
+    const sprite = document.createElement("img");
+    sprite.src = entry.guess.sprite || "";
+    sprite.alt = entry.guess.name;
+    li.appendChild(sprite);
+
+    const info = document.createElement("div");
+    const title = document.createElement("div");
+    title.className = "name";
+    title.textContent = `${entry.guess.name}`;
+    info.appendChild(title);
+
+    const hintsEl = createHintsElement(entry.hints);
+    info.appendChild(hintsEl);
+
+    if (entry.correct) {
+      const rev = document.createElement("div");
+      rev.className = "reveal";
+      rev.textContent = `Congrats! The Pokémon of the day was ${entry.guess.name}.`;
+      info.appendChild(rev);
+      endGame();
+    }
+
+    li.appendChild(info);
+    list.prepend(li);
+  }
+
+  function endGame() {
+    input.disabled = true;
+    const guessButton = form.querySelector("button");
+    if (guessButton) guessButton.disabled = true;
+    form.style.display = "none";
+
+    hintsBox.style.display = "none";
+    showStats();
+  }
+
+  async function showStats() {
+    try {
+      const res = await fetch(archiveDate ? "/api/stats?archive=1" : "/api/stats");
+      const stats = await res.json();
+
+      statsBox.innerHTML = "";
+      const numbers = document.createElement("div");
+      numbers.className = "stats-numbers";
+      [
+        ["Played", stats.played],
+        ["Win %", stats.winRate],
+        ["Current streak", stats.currentStreak],
+        ["Max streak", stats.maxStreak],
+      ].forEach(([label, value]) => {
+        const cell = document.createElement("div");
+        const strong = document.createElement("strong");
+        strong.textContent = value;
+        cell.appendChild(strong);
+        cell.appendChild(document.createTextNode(label));
+        numbers.appendChild(cell);
+      });
+      statsBox.appendChild(numbers);
+
+      const counts = Object.entries(stats.histogram || {})
+        .map(([guesses, count]) => [Number(guesses), count])
+        .sort((a, b) => a[0] - b[0]);
+      const most = Math.max(1, ...counts.map(([, count]) => count));
+      counts.forEach(([guesses, count]) => {
+        const bar = document.createElement("div");
+        bar.className = "stats-bar";
+        const label = document.createElement("span");
+        label.textContent = guesses;
+        const fill = document.createElement("span");
+        fill.textContent = count;
+        fill.style.width = `${Math.max(8, (count / most) * 100)}%`;
+        bar.appendChild(label);
+        bar.appendChild(fill);
+        statsBox.appendChild(bar);
+      });
+
+      const shareButton = document.createElement("button");
+      shareButton.type = "button";
+      shareButton.textContent = "Share";
+      shareButton.addEventListener("click", shareResult);
+      statsBox.appendChild(shareButton);
+
+      statsBox.style.display = "grid";
+    } catch (err) {
+      console.error(err);
+    }
+  }
+
+  // shareResult copies the spoiler-free grid of the day to the clipboard.
+  async function shareResult() {
+    try {
+      const res = await fetch(`/api/share${dateQuery}`);
+      const data = await res.json();
+      if (!data.ok) {
+        statusEl.textContent = data.error;
+        return;
+      }
+      await navigator.clipboard.writeText(data.text);
+      statusEl.style.color = '#e7ecff';
+      statusEl.textContent = "Result copied to the clipboard.";
+    } catch (err) {
+      console.error(err);
+    }
+  }
+
+  // showLoss reveals the target once the last allowed guess missed.
+  function showLoss(reveal) {
+    const li = document.createElement("li");
+    li.className = "guess";
+    if (reveal) {
+      const sprite = document.createElement("img");
+      sprite.src = reveal.sprite || "";
+      sprite.alt = reveal.name;
+      li.appendChild(sprite);
+    }
+
+    const rev = document.createElement("div");
+    rev.className = "reveal";
+    rev.textContent = reveal
+      ? `Out of guesses! The Pokémon of the day was ${reveal.name}.`
+      : "Out of guesses!";
+    li.appendChild(rev);
+    list.prepend(li);
+    endGame();
+  }
+
+  // Rebuild the board of today's guesses after a reload.
+  async function restoreHistory() {
+    try {
+      const res = await fetch(`/api/history${dateQuery}`);
+      const data = await res.json();
+      if (!data.ok || data.guesses.length === 0) return;
+
+      data.guesses.forEach(addGuess);
+      if (data.lost) {
+        showLoss(data.reveal);
+      } else if (!data.solved) {
+        updateStatus(data);
+        await updateHints();
+      }
+    } catch (err) {
+      console.error(err);
+    }
+  }
+  restoreHistory();
+
+  // The subtitle lists the name languages of the running dataset.
+  async function showLangs() {
+    try {
+      const res = await fetch("/api/dataset");
+      const data = await res.json();
+      if (Array.isArray(data.langs) && data.langs.length > 0) {
+        langs = data.langs;
+        langsEl.textContent = ` (${data.langs.map(l => l.toUpperCase()).join("/")} supported)`;
+      }
+    } catch (err) {
+      console.error(err);
+    }
+  }
+  showLangs();
+
+  // The archive lists the past days with the player's status for each.
+  archiveLink.addEventListener("click", async (e) => {
+    e.preventDefault();
+    if (archiveList.style.display !== "none") {
+      archiveList.style.display = "none";
+      return;
+    }
+    try {
+      const res = await fetch("/api/archive");
+      const days = await res.json();
+      const marks = { won: "✅", lost: "❌", playing: "…", new: "" };
+
+      archiveList.innerHTML = "";
+      days.forEach(day => {
+        const li = document.createElement("li");
+        const a = document.createElement("a");
+        a.href = `/?date=${day.date}`;
+        a.textContent = `#${day.puzzle} ${day.date} ${marks[day.status]}`;
+        li.appendChild(a);
+        archiveList.appendChild(li);
+      });
+      archiveList.style.display = "block";
+    } catch (err) {
+      console.error(err);
+    }
+  });
+
   form.addEventListener("submit", async (e) => {
     e.preventDefault();
     const guess = input.value.trim();
@@ -343,7 +567,7 @@ document.addEventListener("DOMContentLoaded", function() {
     suggestBox.style.display = "none";
 
     try {
-      const res = await fetch("/api/guess", {
+      const res = await fetch(`/api/guess${dateQuery}`, {
         method: "POST",
         headers: { "Content-Type": "application/json" },
         body: JSON.stringify({ guess }),
@@ -351,47 +575,22 @@ document.addEventListener("DOMContentLoaded", function() {
       const data = await res.json();
       if (!data.ok) {
         statusEl.textContent = data.error || "Erreur.";
+        if (Array.isArray(data.candidates) && data.candidates.length > 0) {
+          const lang = nameLang();
+          const names = data.candidates.map(c => c.names[lang] || c.names.en);
+          statusEl.textContent += ` Did you mean ${names.join(", ")}?`;
+        }
         statusEl.style.color = 'red';
         return;
       }
 
       updateStatus(data);
-
-      const li = document.createElement("li");
-      li.className = "guess";
-
-      const sprite = document.createElement("img");
-      sprite.src = data.guess.sprite || "";
-      sprite.alt = data.guess.name;
-      li.appendChild(sprite);
-
-      const info = document.createElement("div");
-      const title = document.createElement("div");
-      title.className = "name";
-      title.textContent = `${data.guess.name}`;
-      info.appendChild(title);
-
-      const hintsEl = createHintsElement(data.hints);
-      info.appendChild(hintsEl);
-
-      if (data.correct && data.reveal) {
-        const rev = document.createElement("div");
-        rev.className = "reveal";
-        rev.textContent = `Congrats! The Pokémon of the day was ${data.guess.name}.`;
-        info.appendChild(rev);
-
-        input.disabled = true;
-        const guessButton = form.querySelector("button");
-        if (guessButton) guessButton.disabled = true;
-        form.style.display = "none";
-
-        hintsBox.style.display = "none";
+      addGuess(data);
+      if (data.outcome === "lost") {
+        showLoss(data.reveal);
+      } else {
+        await updateHints();
       }
-
-      li.appendChild(info);
-      list.prepend(li);
-
-      await updateHints();
     } catch (err) {
       statusEl.textContent = "Network Error.";
       statusEl.style.color = 'red';
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stats is a player's record over all the days they finished.
+type Stats struct {
+	Played        int `json:"played"`
+	Won           int `json:"won"`
+	CurrentStreak int `json:"currentStreak"`
+	MaxStreak     int `json:"maxStreak"`
+	// Histogram counts the wins by number of guesses.
+	Histogram  map[int]int `json:"histogram"`
+	LastPlayed string      `json:"lastPlayed,omitempty"`
+	LastWon    string      `json:"lastWon,omitempty"`
+}
+
+// record counts the game of key once it is won or lost, in the archive
+// stats for an archive key. A game is counted once, whatever the number of
+// requests that finish it. Streaks are only kept for the daily games.
+func (st *SessionStore) record(id, key string, won bool, guesses int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	sess, ok := st.sessions[id]
+	if !ok {
+		return
+	}
+	ds, ok := sess.Days[key]
+	if !ok || ds.Counted {
+		return
+	}
+	ds.Counted = true
+
+	s := &sess.Stats
+	if isArchiveKey(key) {
+		s = &sess.Archive
+	}
+	day := strings.TrimPrefix(key, archivePrefix)
+	s.Played++
+	s.LastPlayed = day
+	if won {
+		s.Won++
+		if !isArchiveKey(key) {
+			if s.LastWon == previousDay(day) {
+				s.CurrentStreak++
+			} else {
+				s.CurrentStreak = 1
+			}
+			s.MaxStreak = max(s.MaxStreak, s.CurrentStreak)
+		}
+		s.LastWon = day
+		if s.Histogram == nil {
+			s.Histogram = make(map[int]int)
+		}
+		s.Histogram[guesses]++
+	} else {
+		s.CurrentStreak = 0
+	}
+	st.dirty[id] = true
+}
+
+// stats returns a copy of the player's daily or archive stats as of day.
+// The current streak is 0 once a day went by without a win.
+func (st *SessionStore) stats(id, day string, archive bool) Stats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var s Stats
+	if sess, ok := st.sessions[id]; ok {
+		s = sess.Stats
+		if archive {
+			s = sess.Archive
+		}
+	}
+	hist := make(map[int]int, len(s.Histogram))
+	for n, count := range s.Histogram {
+		hist[n] = count
+	}
+	s.Histogram = hist
+	if s.LastWon != day && s.LastWon != previousDay(day) {
+		s.CurrentStreak = 0
+	}
+	return s
+}
+
+// previousDay returns the dayKey of the day before day.
+func previousDay(day string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return ""
+	}
+	return dayKey(t.AddDate(0, 0, -1))
+}
+
+// handleStats returns the player's daily stats, or the archive ones with
+// ?archive=1.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	day := dayKey(time.Now().UTC())
+	sid := s.sessions.sessionID(r)
+	stats := s.sessions.stats(sid, day, r.URL.Query().Get("archive") == "1")
+
+	winRate := 0
+	if stats.Played > 0 {
+		winRate = stats.Won * 100 / stats.Played
+	}
+	writeJSON(w, map[string]any{
+		"played":        stats.Played,
+		"won":           stats.Won,
+		"winRate":       winRate,
+		"currentStreak": stats.CurrentStreak,
+		"maxStreak":     stats.MaxStreak,
+		"histogram":     stats.Histogram,
+	})
+}
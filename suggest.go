@@ -0,0 +1,123 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultSuggestLimit = 20
+	maxSuggestLimit     = 100
+)
+
+// suggestion is one name a prefix query can return.
+type suggestion struct {
+	id   int
+	lang string
+	name string
+}
+
+// trieNode indexes normalized keys rune by rune. Every node keeps the
+// names whose key ends there and, shortest first, all the names below it,
+// so a query costs one walk down the trie plus the results it returns.
+type trieNode struct {
+	children map[rune]*trieNode
+	exact    []suggestion
+	below    []suggestion
+}
+
+// buildIndexes builds the lookup structures over the final rows.
+// loadDataset calls it once the forms are merged.
+func (n *NameIndex) buildIndexes() {
+	n.byID = make(map[int]NamesRow, len(n.rows))
+	for _, row := range n.rows {
+		n.byID[row.ID] = row
+	}
+	n.buildTrie()
+	n.buildBKTree()
+}
+
+// buildTrie indexes every name of every row, and the romaji of kana names.
+func (n *NameIndex) buildTrie() {
+	root := &trieNode{}
+	for _, row := range n.rows {
+		for _, lang := range n.langs {
+			name, ok := row.Names[lang]
+			if !ok {
+				continue
+			}
+			s := suggestion{id: row.ID, lang: lang, name: name}
+			for _, key := range append([]string{normalizeKey(name)}, romajiKeys(name)...) {
+				root.insert(key, s)
+			}
+		}
+	}
+	root.sort()
+	n.trie = root
+}
+
+func (t *trieNode) insert(key string, s suggestion) {
+	node := t
+	for _, r := range key {
+		child, ok := node.children[r]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[rune]*trieNode)
+			}
+			child = &trieNode{}
+			node.children[r] = child
+		}
+		node = child
+		// The keys of one name share their prefixes (pikachuu, pikachu).
+		if len(node.below) == 0 || node.below[len(node.below)-1] != s {
+			node.below = append(node.below, s)
+		}
+	}
+	if !slices.Contains(node.exact, s) {
+		node.exact = append(node.exact, s)
+	}
+}
+
+func (t *trieNode) sort() {
+	slices.SortStableFunc(t.below, func(a, b suggestion) int {
+		if d := utf8.RuneCountInString(a.name) - utf8.RuneCountInString(b.name); d != 0 {
+			return d
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	for _, child := range t.children {
+		child.sort()
+	}
+}
+
+// suggest returns up to limit names starting with the normalized query q:
+// exact matches first, then names in lang, then shorter names. A name
+// shared by several languages is returned once, and the Pokémon in skip
+// not at all.
+func (n *NameIndex) suggest(q, lang string, limit int, skip map[int]bool) []suggestion {
+	node := n.trie
+	for _, r := range q {
+		if node = node.children[r]; node == nil {
+			return nil
+		}
+	}
+
+	var out []suggestion
+	seen := make(map[string]bool)
+	for _, list := range [][]suggestion{node.exact, node.below} {
+		for _, preferred := range []bool{true, false} {
+			for _, s := range list {
+				if len(out) == limit {
+					return out
+				}
+				if (s.lang == lang) != preferred || seen[s.name] || skip[s.id] {
+					continue
+				}
+				seen[s.name] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxEvolutionPosition is the last stage of the longest evolution lines.
+const maxEvolutionPosition = 2
+
+// runValidate is "./pokedle validate": it loads data/ like the server does,
+// runs the cross-file checks on top and exits non-zero on any problem.
+func runValidate() {
+	wd, _ := os.Getwd()
+	dataDir := filepath.Join(wd, "data")
+
+	data, err := loadDataset(dataDir, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid dataset in %s:\n%v\n", dataDir, err)
+		os.Exit(1)
+	}
+
+	problems := validateDataset(data)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: %d problems\n", dataDir, len(problems))
+		os.Exit(1)
+	}
+	fmt.Printf("%s: %d names, %d forms, no problems\n", dataDir, len(data.names.rows), len(data.forms))
+}
+
+// validateDataset returns the problems that loading alone lets through,
+// because each file is valid on its own: IDs missing from the gen or
+// evolution files (they silently become 0 in hints), out-of-range values
+// and forms whose gen or position were left empty. Names shared by two
+// Pokémon already fail loadDataset.
+func validateDataset(data *Dataset) []string {
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	isForm := make(map[int]bool, len(data.forms))
+	for _, f := range data.forms {
+		isForm[f.ID] = true
+		if len(f.blank) > 0 {
+			report("%s: id %d (%s) has no %s", formsFile, f.ID, f.Names["en"], strings.Join(f.blank, ", "))
+		}
+	}
+
+	for _, row := range data.names.rows {
+		if !isForm[row.ID] {
+			if _, ok := data.gen[row.ID]; !ok {
+				report("%s: id %d (%s) has no row in %s", namesFile, row.ID, row.Names["en"], genFile)
+			}
+			if _, ok := data.evo[row.ID]; !ok {
+				report("%s: id %d (%s) has no row in %s", namesFile, row.ID, row.Names["en"], evolutionFile)
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(data.evo)) {
+		evo := data.evo[id]
+		if evo.Position < 0 || evo.Position > maxEvolutionPosition {
+			report("%s: id %d has position %d, want 0 to %d", evolutionFile, id, evo.Position, maxEvolutionPosition)
+		}
+		if evo.IsFullyEvolved != 0 && evo.IsFullyEvolved != 1 {
+			report("%s: id %d has is_fully_evolved %d, want 0 or 1", evolutionFile, id, evo.IsFullyEvolved)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(data.gen)) {
+		if gen := data.gen[id]; gen < 1 && !isForm[id] {
+			report("%s: id %d has gen %d", genFile, id, gen)
+		}
+	}
+
+	return problems
+}